@@ -0,0 +1,59 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/viper"
+
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+// addrFlag parses the address given in flag, or reads it from the deployment record of a "@Name" reference.
+func addrFlag(flag string) (common.Address, error) {
+	value := viper.GetString(flag)
+	if strings.HasPrefix(value, "@") {
+		addr, err := deployedAddr(value)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("--%s: %s", flag, err.Error())
+		}
+		return addr, nil
+	}
+	addr, err := utils.ParseBSCAddr(value)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("--%s: %s", flag, err.Error())
+	}
+	return addr, nil
+}
+
+// contractAddrFlag parses the address given in flag and checks that there is contract code at it.
+func contractAddrFlag(ethClient *ethclient.Client, flag string) (common.Address, error) {
+	addr, err := addrFlag(flag)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if err := utils.RequireContract(ethClient, addr); err != nil {
+		return common.Address{}, fmt.Errorf("--%s: %s", flag, err.Error())
+	}
+	return addr, nil
+}
+
+// recipientAddrFlag parses the address given in flag, which receives tokens, BNB or an ownership, and warns on stderr,
+// in every output mode, when it is a contract or a system contract.
+func recipientAddrFlag(ethClient *ethclient.Client, flag string) (common.Address, error) {
+	addr, err := addrFlag(flag)
+	if err != nil {
+		return common.Address{}, err
+	}
+	warning, err := utils.CheckRecipient(ethClient, addr)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(warning) != 0 {
+		fmt.Fprintf(os.Stderr, "Warning: --%s %s\n", flag, warning)
+	}
+	return addr, nil
+}
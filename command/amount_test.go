@@ -0,0 +1,89 @@
+package command
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+)
+
+func TestPeggyAmountFlag(t *testing.T) {
+	defer viper.Reset()
+	// A bare integer used to count BEP2 units, it must not silently become whole tokens.
+	viper.Set(constValue.PeggyAmount, "100000000")
+	_, err := peggyAmountFlag("ABC-123")
+	require.EqualError(t, err, `--peggy-amount 100000000: a bare integer used to count BEP2 units of 8 decimals and now needs a unit, for the same amount pass "1 ABC" in whole tokens or "100000000 wei" in units of 8 decimals`)
+
+	for input, expected := range map[string]int64{"100000000 wei": 100000000, "1 ABC": 100000000, "1.5": 150000000, "1K": 100000000000} {
+		viper.Set(constValue.PeggyAmount, input)
+		amount, err := peggyAmountFlag("ABC-123")
+		require.NoError(t, err, input)
+		require.Equal(t, big.NewInt(expected), amount, input)
+	}
+}
+
+func TestBareAmountError(t *testing.T) {
+	// --total-supply used to scale BEP2 units to the BEP20 decimals, its wei counts the BEP20 smallest unit.
+	err := bareAmountError("total-supply", "150000000", "ABC", 18)
+	require.EqualError(t, err, `--total-supply 150000000: a bare integer used to count BEP2 units of 8 decimals and now needs a unit, for the same amount pass "1.5 ABC" in whole tokens or "1500000000000000000 wei" in units of 18 decimals`)
+	// With less than 8 decimals the legacy amount may have no wei equivalent.
+	err = bareAmountError("total-supply", "150000001", "ABC", 2)
+	require.EqualError(t, err, `--total-supply 150000001: a bare integer used to count BEP2 units of 8 decimals and now needs a unit, for the same amount pass "1.50000001 ABC" in whole tokens`)
+
+	for _, input := range []string{"", "1.5", "1.5M", "2e6", "100 ABC", "100 wei"} {
+		require.NoError(t, bareAmountError("total-supply", input, "ABC", 18), input)
+	}
+}
+
+func TestBindConfigDefaults(t *testing.T) {
+	defer viper.Reset()
+	dir, err := ioutil.TempDir("", "bindconfig")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	configPath := filepath.Join(dir, "deploy.yaml")
+	require.NoError(t, ioutil.WriteFile(configPath, []byte(`version: 1
+contracts:
+  - name: token
+    contract_data: "6080"
+    bep2_symbol: ABC-123
+    peggy_amount: 1000
+    owner: "0x0000000000000000000000000000000000001004"
+  - name: vault
+    contract_data: "6080"
+`), 0644))
+
+	// Without --config-path nothing is seeded.
+	require.NoError(t, bindConfigDefaults(true))
+	require.Empty(t, viper.GetString(constValue.BEP2Symbol))
+
+	viper.Set(constValue.ConfigPath, configPath)
+	viper.Set(constValue.Contract, "token")
+	require.NoError(t, bindConfigDefaults(false))
+	require.Equal(t, "ABC-123", viper.GetString(constValue.BEP2Symbol))
+	require.Empty(t, viper.GetString(constValue.BEP20Owner))
+	// A bare number in the config counts whole tokens and passes --peggy-amount.
+	amount, err := peggyAmountFlag(viper.GetString(constValue.BEP2Symbol))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(100000000000), amount)
+
+	require.NoError(t, bindConfigDefaults(true))
+	require.Equal(t, "0x0000000000000000000000000000000000001004", viper.GetString(constValue.BEP20Owner))
+
+	// The flags win over the config.
+	viper.Set(constValue.BEP2Symbol, "XYZ-456")
+	viper.Set(constValue.PeggyAmount, "5 XYZ")
+	require.NoError(t, bindConfigDefaults(true))
+	require.Equal(t, "XYZ-456", viper.GetString(constValue.BEP2Symbol))
+	amount, err = peggyAmountFlag("XYZ-456")
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(500000000), amount)
+
+	viper.Set(constValue.Contract, "")
+	require.Error(t, bindConfigDefaults(false))
+}
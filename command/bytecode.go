@@ -0,0 +1,131 @@
+package command
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/types"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+func InspectBytecodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspectBytecode",
+		Short: "Statically check a BEP20 byte code, from the config file before deploy or from a deployed contract, for the methods a bind needs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := viper.GetString(constValue.ConfigPath)
+			bep20ContractAddr := viper.GetString(constValue.BEP20ContractAddr)
+			if (len(configPath) == 0) == (len(bep20ContractAddr) == 0) {
+				return fmt.Errorf("expect exactly one of --%s and --%s", constValue.ConfigPath, constValue.BEP20ContractAddr)
+			}
+
+			var report *utils.BytecodeReport
+			if len(configPath) != 0 {
+				_, configData, err := readContractConfig()
+				if err != nil {
+					return err
+				}
+				viper.SetDefault(constValue.BEP2Symbol, configData.BEP2Symbol)
+				code, err := hex.DecodeString(configData.ContractData)
+				if err != nil {
+					return err
+				}
+				report = utils.AnalyzeBytecode(code)
+			} else {
+				contractAddr, err := addrFlag(constValue.BEP20ContractAddr)
+				if err != nil {
+					return err
+				}
+				ethClient, _, err := getEnv()
+				if err != nil {
+					return err
+				}
+				report, err = utils.InspectContract(ethClient, contractAddr)
+				if err != nil {
+					return err
+				}
+			}
+
+			var mintableWarning string
+			if bep2Symbol := viper.GetString(constValue.BEP2Symbol); len(bep2Symbol) != 0 {
+				tokenClient, err := getBcTokenClient()
+				if err != nil {
+					return err
+				}
+				bep2Token, err := tokenClient.GetBep2Token(bep2Symbol)
+				if err != nil {
+					return err
+				}
+				mintableWarning = report.MintableMismatch(bep2Token.Mintable)
+			}
+
+			if viper.GetString(constValue.Output) == constValue.OutputJSON {
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+			} else {
+				if report.IsProxy {
+					fmt.Println(fmt.Sprintf("EIP-1967 proxy, implementation: %s", report.Implementation))
+				}
+				fmt.Println(fmt.Sprintf("Function selectors: %s", strings.Join(report.Selectors, " ")))
+				if len(report.MissingMethods) == 0 {
+					fmt.Println("All required BEP20 methods are implemented")
+				} else {
+					fmt.Println(fmt.Sprintf("Missing BEP20 methods: %s", strings.Join(report.MissingMethods, ", ")))
+				}
+				fmt.Println(fmt.Sprintf("mint: %t, burn: %t", report.HasMint, report.HasBurn))
+			}
+			if len(mintableWarning) != 0 {
+				fmt.Println("Warning: " + mintableWarning)
+			}
+			if len(report.MissingMethods) != 0 {
+				return fmt.Errorf("the byte code misses %d required BEP20 methods", len(report.MissingMethods))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String(constValue.ConfigPath, "", "config file path, inspect the contract byte code before deploy")
+	cmd.Flags().String(constValue.Contract, "", "name of the contract to inspect, when the config has several")
+	cmd.Flags().String(constValue.BEP20ContractAddr, "", "bep20 contract address, inspect a deployed contract")
+	cmd.Flags().String(constValue.BEP2Symbol, "", "bep2 token symbol, compare mint with the BEP2 mintable flag, default to the bep2_symbol in the config")
+	cmd.Flags().String(constValue.BcApiUrl, "", "Beacon Chain api url, default to the api of the selected network")
+	cmd.Flags().String(constValue.BcTokenFile, "", "local json file with the BEP2 token list, used instead of the Beacon Chain api")
+	cmd.Flags().String(constValue.Output, constValue.OutputText, "output format, text or json")
+	return cmd
+}
+
+func checkBytecode(ctx *preCheckContext) (*types.Finding, error) {
+	report, err := utils.InspectContract(ctx.ethClient, ctx.bep20ContractAddr)
+	if err != nil {
+		return nil, err
+	}
+	finding := &types.Finding{
+		ID:       "BEP20_BYTECODE",
+		Title:    "Checking BEP20 byte code implements the required methods",
+		Severity: types.SeverityWarning,
+		Passed:   len(report.MissingMethods) == 0,
+		Expected: strings.Join(utils.RequiredBEP20Methods, ", "),
+	}
+	if report.IsProxy {
+		finding.Title = fmt.Sprintf("%s, following EIP-1967 proxy to %s", finding.Title, report.Implementation)
+	}
+	if !finding.Passed {
+		finding.Observed = "missing " + strings.Join(report.MissingMethods, ", ")
+		finding.Message = "the BEP20 byte code does not contain the selectors of some required methods"
+		finding.Suggestion = "please upgrade your contract to follow BEP20 standards"
+	}
+	if mintableWarning := report.MintableMismatch(ctx.bep2Token.Mintable); len(mintableWarning) != 0 {
+		finding.Passed = false
+		finding.Message = strings.TrimPrefix(finding.Message+"; "+mintableWarning, "; ")
+		finding.Suggestion = strings.TrimPrefix(finding.Suggestion+"; implement mint only if the BEP2 token is mintable", "; ")
+	}
+	return finding, nil
+}
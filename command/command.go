@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -18,20 +17,18 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/usbwallet"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
-	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	"github.com/binance-chain/token-bind-tool/config"
 	constValue "github.com/binance-chain/token-bind-tool/const"
 	"github.com/binance-chain/token-bind-tool/contracts/bep20"
 	"github.com/binance-chain/token-bind-tool/contracts/ownable"
 	"github.com/binance-chain/token-bind-tool/contracts/tokenhub"
 	tokenmanager "github.com/binance-chain/token-bind-tool/contracts/tokenmanger"
-	"github.com/binance-chain/token-bind-tool/types"
 	"github.com/binance-chain/token-bind-tool/utils"
 )
 
@@ -108,29 +105,81 @@ func openLedger(index uint32) (accounts.Wallet, accounts.Account, error) {
 	return wallet, ledgerAccount, nil
 }
 
+// openSigner opens the Ledger account with --ledger, otherwise the temp account in --keystore-path.
+func openSigner(chainId *big.Int) (accounts.Wallet, accounts.Account, error) {
+	if viper.GetBool(constValue.Ledger) {
+		return openLedger(uint32(viper.GetInt32(constValue.LedgerAccountIndex)))
+	}
+	keyStore, tempAccount, err := generateOrGetTempAccount(viper.GetString(constValue.KeystorePath), chainId)
+	if err != nil {
+		return nil, accounts.Account{}, err
+	}
+	for _, wallet := range keyStore.Wallets() {
+		if wallet.Contains(tempAccount) {
+			return wallet, tempAccount, nil
+		}
+	}
+	return nil, accounts.Account{}, fmt.Errorf("no wallet of the temp account %s", tempAccount.Address.String())
+}
+
+// addSignerFlags adds the flags openSigner reads.
+func addSignerFlags(cmd *cobra.Command) {
+	cmd.Flags().String(constValue.KeystorePath, constValue.BindKeystore, "keystore path of the temp account, which signs unless --ledger is set")
+	cmd.Flags().Bool(constValue.Ledger, false, "sign with a ledger account instead of the temp account")
+	cmd.Flags().Int64(constValue.LedgerAccountIndex, 0, "ledger account index, with --ledger")
+}
+
 func getEnv() (*ethclient.Client, *big.Int, error) {
+	rpcClient, chainId, err := getRPCEnv()
+	if err != nil {
+		return nil, chainId, err
+	}
+	return ethclient.NewClient(rpcClient), chainId, nil
+}
+
+func getRPCEnv() (*rpc.Client, *big.Int, error) {
+	rpcUrl, chainId, err := getRPCUrl()
+	if err != nil {
+		return nil, nil, err
+	}
+	rpcClient, err := rpc.DialContext(context.Background(), rpcUrl)
+	if err != nil {
+		return nil, chainId, err
+	}
+	return rpcClient, chainId, nil
+}
+
+func getRPCUrl() (string, *big.Int, error) {
 	networkType := viper.GetString(constValue.NetworkType)
-	if networkType != constValue.TestNet && networkType != constValue.Mainnet {
-		return nil, nil, fmt.Errorf("unsupported network type")
-	}
-	var rpcClient *rpc.Client
-	var err error
-	var chainId *big.Int
-	if networkType == constValue.Mainnet {
-		rpcClient, err = rpc.DialContext(context.Background(), constValue.MainnnetRPC)
+	rpcUrl := viper.GetString(constValue.RPCUrl)
+	switch networkType {
+	case constValue.Mainnet:
+		if len(rpcUrl) == 0 {
+			rpcUrl = constValue.MainnnetRPC
+		}
+		return rpcUrl, big.NewInt(constValue.MainnetChainID), nil
+	case constValue.TestNet:
+		if len(rpcUrl) == 0 {
+			rpcUrl = constValue.TestnetRPC
+		}
+		return rpcUrl, big.NewInt(constValue.TestnetChainID), nil
+	case constValue.Local:
+		if len(rpcUrl) == 0 {
+			return "", nil, fmt.Errorf("--%s is required with --%s %s", constValue.RPCUrl, constValue.NetworkType, constValue.Local)
+		}
+		ethClient, err := ethclient.Dial(rpcUrl)
 		if err != nil {
-			return nil, chainId, err
+			return "", nil, err
 		}
-		chainId = big.NewInt(constValue.MainnetChainID)
-	} else {
-		rpcClient, err = rpc.DialContext(context.Background(), constValue.TestnetRPC)
+		defer ethClient.Close()
+		chainId, err := ethClient.ChainID(context.Background())
 		if err != nil {
-			return nil, chainId, err
+			return "", nil, fmt.Errorf("failed to get the chain id of %s: %s", rpcUrl, err.Error())
 		}
-		chainId = big.NewInt(constValue.TestnetChainID)
+		return rpcUrl, chainId, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported network type")
 	}
-	return ethclient.NewClient(rpcClient), chainId, nil
-
 }
 
 func InitKeyCmd() *cobra.Command {
@@ -157,15 +206,35 @@ func InitKeyCmd() *cobra.Command {
 
 func DeployContractCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "deployContract --config-path {config-path}",
-		Short: "Deploy a contract",
+		Use:   "deployContract --config-path {config-path} | --artifact {artifact path}",
+		Short: "Deploy the contracts in the config, in order",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			deployConfig, err := readDeployConfig()
+			if err != nil {
+				return err
+			}
+			gas, err := configDeployGas(deployConfig)
+			if err != nil {
+				return err
+			}
 			ethClient, chainId, err := getEnv()
 			if err != nil {
 				return err
 			}
-			configPath := viper.GetString(constValue.ConfigPath)
-			configData, err := config.ReadConfigData(configPath)
+			salts := make([]*[32]byte, 0, len(deployConfig.Contracts))
+			for _, contract := range deployConfig.Contracts {
+				salt, err := saltFlag(contract.Salt)
+				if err != nil {
+					return err
+				}
+				if salt != nil {
+					if err := requireCreate2Owner(contract); err != nil {
+						return err
+					}
+				}
+				salts = append(salts, salt)
+			}
+			factory, err := create2FactoryFlag()
 			if err != nil {
 				return err
 			}
@@ -174,16 +243,57 @@ func DeployContractCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			contractAddr, err := DeployContractFromTempAccount(ethClient, keyStore, tempAccount, configData.ContractData, chainId)
-			if err != nil {
-				return err
+			if viper.GetBool(constValue.DryRun) {
+				plan, err := newDryRunPlan(ethClient, cmd.Name(), tempAccount.Address, chainId)
+				if err != nil {
+					return err
+				}
+				for idx, contract := range deployConfig.Contracts {
+					if salts[idx] != nil {
+						_, err = planCreate2Deploy(plan, factory, *salts[idx], contract.ContractData, gas)
+					} else {
+						_, err = planDeploy(plan, contract.ContractData, gas)
+					}
+					if err != nil {
+						return err
+					}
+					if contract.BuildArtifact != nil {
+						deployTx := plan.Transactions[len(plan.Transactions)-1]
+						deployTx.Note += fmt.Sprintf("; %s artifact %s sha256 %s", contract.BuildArtifact.Format, contract.Artifact, contract.BuildArtifact.Hash)
+					}
+				}
+				return printDryRunPlan(plan)
+			}
+			deployments := make([]*utils.Deployment, 0, len(deployConfig.Contracts))
+			for idx, contract := range deployConfig.Contracts {
+				if contract.BuildArtifact != nil {
+					printNotice(fmt.Sprintf("Deploy %s from the %s artifact %s, sha256 %s", contract.BuildArtifact.ContractName, contract.BuildArtifact.Format, contract.Artifact, contract.BuildArtifact.Hash))
+				}
+				var deployment *utils.Deployment
+				if salts[idx] != nil {
+					deployment, err = DeployCreate2FromTempAccount(ethClient, keyStore, tempAccount, factory, *salts[idx], contract.ContractData, gas, chainId)
+				} else {
+					deployment, err = DeployContractFromTempAccount(ethClient, keyStore, tempAccount, contract.ContractData, gas, chainId)
+				}
+				if err != nil {
+					return err
+				}
+				if err := recordContractDeployment(deployment, contract); err != nil {
+					return err
+				}
+				deployments = append(deployments, deployment)
 			}
-			fmt.Println(contractAddr.String())
-			return nil
+			return printDeployments(deployments)
 		},
 	}
 	cmd.Flags().String(constValue.KeystorePath, constValue.BindKeystore, "keystore path")
 	cmd.Flags().String(constValue.ConfigPath, "", "config file path")
+	cmd.Flags().String(constValue.Artifact, "", "Foundry, Hardhat or Truffle artifact to deploy instead of --config-path")
+	cmd.Flags().StringSlice(constValue.Link, nil, "library address for the artifact, like Lib=0x... or path/Lib.sol:Lib=0x..., repeatable")
+	cmd.Flags().String(constValue.Salt, "", "deploy with CREATE2 and this salt the contracts without salt in the config, see predictAddress")
+	cmd.Flags().String(constValue.Create2Factory, utils.Create2FactoryAddr.String(), "CREATE2 factory, default to the deterministic deployment proxy")
+	cmd.Flags().Bool(constValue.DryRun, false, "print the planned transactions and exit without signing")
+	cmd.Flags().String(constValue.Output, constValue.OutputText, "output format, text or json, which prints the deployment records")
 
 	return cmd
 }
@@ -208,8 +318,7 @@ func DeployCanonicalContractCmd() *cobra.Command {
 				return err
 			}
 
-			canonicalImplAddr := viper.GetString(flagCanonicalImplAddr)
-			err = utils.ValidateBSCAddr(canonicalImplAddr)
+			canonicalImplAddr, err := contractAddrFlag(ethClient, flagCanonicalImplAddr)
 			if err != nil {
 				return err
 			}
@@ -221,28 +330,32 @@ func DeployCanonicalContractCmd() *cobra.Command {
 			}
 
 			decimals := viper.GetInt(flagDecimals)
-			if decimals < 0 {
-				return fmt.Errorf("decimals must not be negative")
+			if decimals < 0 || decimals > utils.MaxBEP20Decimals {
+				return fmt.Errorf("decimals must be between 0 and %d", utils.MaxBEP20Decimals)
 			}
 
 			totalSupplyStr := viper.GetString(flagTotalSupply)
 			if len(totalSupplyStr) == 0 {
 				return fmt.Errorf("missing total supply")
 			}
-			totalSupply := big.NewInt(0)
-			totalSupply.SetString(totalSupplyStr, 10)
-			totalSupply = utils.ConvertToBEP20Amount(totalSupply, int64(decimals))
+			if err := bareAmountError(flagTotalSupply, totalSupplyStr, symbol, int64(decimals)); err != nil {
+				return err
+			}
+			totalSupplyAmount, err := utils.ParseAmount(totalSupplyStr, int64(decimals), symbol)
+			if err != nil {
+				return fmt.Errorf("--%s: %s", flagTotalSupply, err.Error())
+			}
+			printNotice(fmt.Sprintf("Total supply: %s", totalSupplyAmount.String()))
+			totalSupply := totalSupplyAmount.Raw
 
 			mintable := viper.GetBool(flagMintable)
 
-			owner := viper.GetString(flagOwner)
-			err = utils.ValidateBSCAddr(owner)
+			owner, err := recipientAddrFlag(ethClient, flagOwner)
 			if err != nil {
 				return err
 			}
 
-			proxyAdmin := viper.GetString(flagProxyAdmin)
-			err = utils.ValidateBSCAddr(proxyAdmin)
+			proxyAdmin, err := recipientAddrFlag(ethClient, flagProxyAdmin)
 			if err != nil {
 				return err
 			}
@@ -258,7 +371,7 @@ func DeployCanonicalContractCmd() *cobra.Command {
 				return err
 			}
 
-			abiEncodingInitialize, err := canonicalUpgradeableBEP20.Pack("initialize", name, symbol, uint8(decimals), totalSupply, mintable, common.HexToAddress(owner))
+			abiEncodingInitialize, err := canonicalUpgradeableBEP20.Pack("initialize", name, symbol, uint8(decimals), totalSupply, mintable, owner)
 			if err != nil {
 				return err
 			}
@@ -268,18 +381,50 @@ func DeployCanonicalContractCmd() *cobra.Command {
 				return err
 			}
 
-			abiEncodingConstructor, err := upgradeableProxyABI.Pack("", common.HexToAddress(canonicalImplAddr), common.HexToAddress(proxyAdmin), abiEncodingInitialize)
+			abiEncodingConstructor, err := upgradeableProxyABI.Pack("", canonicalImplAddr, proxyAdmin, abiEncodingInitialize)
 			if err != nil {
 				return err
 			}
-			abiEncodingConstructorStr := hex.EncodeToString(abiEncodingConstructor)
+			contractData := constValue.CanonicalUpgradeableBEP20BytesCode + hex.EncodeToString(abiEncodingConstructor)
 
-			contractAddr, err := DeployContractFromTempAccount(ethClient, keyStore, tempAccount, constValue.CanonicalUpgradeableBEP20BytesCode+abiEncodingConstructorStr, chainId)
+			salt, err := saltFlag("")
+			if err != nil {
+				return err
+			}
+			factory, err := create2FactoryFlag()
+			if err != nil {
+				return err
+			}
+			if viper.GetBool(constValue.DryRun) {
+				plan, err := newDryRunPlan(ethClient, cmd.Name(), tempAccount.Address, chainId)
+				if err != nil {
+					return err
+				}
+				if salt != nil {
+					_, err = planCreate2Deploy(plan, factory, *salt, contractData, defaultDeployGas())
+				} else {
+					_, err = planDeploy(plan, contractData, defaultDeployGas())
+				}
+				if err != nil {
+					return err
+				}
+				return printDryRunPlan(plan)
+			}
+			var deployment *utils.Deployment
+			if salt != nil {
+				deployment, err = DeployCreate2FromTempAccount(ethClient, keyStore, tempAccount, factory, *salt, contractData, defaultDeployGas(), chainId)
+			} else {
+				deployment, err = DeployContractFromTempAccount(ethClient, keyStore, tempAccount, contractData, defaultDeployGas(), chainId)
+			}
 			if err != nil {
 				return err
 			}
-			fmt.Println(contractAddr.String())
-			return nil
+			deployment.EncodedConstructorArgs = abiEncodingConstructor
+			deployment.ABI = json.RawMessage(constValue.UpgradeableProxyABI)
+			if err := recordDeployment(deployment, symbol); err != nil {
+				return err
+			}
+			return printDeployments([]*utils.Deployment{deployment})
 		},
 	}
 	cmd.Flags().String(constValue.KeystorePath, constValue.BindKeystore, "keystore path")
@@ -287,10 +432,14 @@ func DeployCanonicalContractCmd() *cobra.Command {
 	cmd.Flags().String(flagName, "", "token name")
 	cmd.Flags().String(flagSymbol, "", "token symbol")
 	cmd.Flags().Int(flagDecimals, 18, "token decimals")
-	cmd.Flags().String(flagTotalSupply, "", "total supply")
+	cmd.Flags().String(flagTotalSupply, "", "total supply in whole tokens like \"1.5M\" or \"1000 ABC\", or in the smallest unit like \"1e24 wei\"")
 	cmd.Flags().Bool(flagMintable, true, "mintable")
 	cmd.Flags().String(flagOwner, "", "bep20 token owner")
 	cmd.Flags().String(flagProxyAdmin, "", "proxy admin")
+	cmd.Flags().String(constValue.Salt, "", "deploy with CREATE2 and this salt, see predictAddress")
+	cmd.Flags().String(constValue.Create2Factory, utils.Create2FactoryAddr.String(), "CREATE2 factory, default to the deterministic deployment proxy")
+	cmd.Flags().Bool(constValue.DryRun, false, "print the planned transactions and exit without signing")
+	cmd.Flags().String(constValue.Output, constValue.OutputText, "output format, text or json, which prints the deployment record")
 
 	return cmd
 }
@@ -300,6 +449,9 @@ func ApproveBindAndTransferOwnershipCmd() *cobra.Command {
 		Use:   "approveBindAndTransferOwnership --config-path {config path}",
 		Short: "Use temp account in the keystore to approveBind. Then transfer bep20 ownership and rest bep20 balance to the ledger account(specified in the config file). This command supposes that the temp account in the keystore is the owner of the bep20 token",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bindConfigDefaults(true); err != nil {
+				return err
+			}
 			if viper.GetString(constValue.NetworkType) == constValue.TestNet && viper.GetString(constValue.PeggyAmount) == "" {
 				return fmt.Errorf("on testnet, you must specify peggy amount manually")
 			}
@@ -307,12 +459,12 @@ func ApproveBindAndTransferOwnershipCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			bep20ContractAddr := viper.GetString(constValue.BEP20ContractAddr)
-			if !strings.HasPrefix(bep20ContractAddr, "0x") || len(bep20ContractAddr) != constValue.BSCAddrLength {
-				return fmt.Errorf("invalid bep20 contract address")
+			bep20ContractAddr, err := contractAddrFlag(ethClient, constValue.BEP20ContractAddr)
+			if err != nil {
+				return err
 			}
-			bep20Owner := viper.GetString(constValue.BEP20Owner)
-			if utils.ValidateBSCAddr(bep20Owner) != nil {
+			bep20Owner, err := recipientAddrFlag(ethClient, constValue.BEP20Owner)
+			if err != nil {
 				return err
 			}
 			bep2Symbol := viper.GetString(constValue.BEP2Symbol)
@@ -327,17 +479,48 @@ func ApproveBindAndTransferOwnershipCmd() *cobra.Command {
 			}
 			var peggyAmount *big.Int
 			if viper.GetString(constValue.NetworkType) == constValue.TestNet {
-				peggyAmount = big.NewInt(0)
-				peggyAmount.SetString(viper.GetString(constValue.PeggyAmount), 10)
+				peggyAmount, err = peggyAmountFlag(bep2Symbol)
+				if err != nil {
+					return err
+				}
+			}
+			if err := checkDryRunFlags(); err != nil {
+				return err
+			}
+			if viper.GetBool(constValue.DryRun) {
+				plan, err := newDryRunPlan(ethClient, cmd.Name(), tempAccount.Address, chainId)
+				if err != nil {
+					return err
+				}
+				if err := planApproveBind(plan, ethClient, bep20ContractAddr, bep2Symbol, peggyAmount, &bep20Owner, &bep20Owner); err != nil {
+					return err
+				}
+				return printDryRunPlan(plan)
+			}
+			if viper.GetBool(constValue.Simulate) {
+				return simulateApproveBindCmd(ethClient, tempAccount.Address, bep20ContractAddr, bep2Symbol, peggyAmount, bep20Owner, true)
+			}
+			err = confirmOperation(chainId, func() (*confirmSummary, error) {
+				return approveBindSummary(ethClient, tempAccount.Address, bep20ContractAddr, bep2Symbol, peggyAmount, bep20Owner)
+			})
+			if err != nil {
+				return err
 			}
-			return ApproveBindAndTransferOwnershipAndRestBalanceBackToLedgerAccount(ethClient, keyStore, tempAccount, common.HexToAddress(bep20ContractAddr), peggyAmount, bep2Symbol, common.HexToAddress(bep20Owner), chainId)
+			return ApproveBindAndTransferOwnershipAndRestBalanceBackToLedgerAccount(ethClient, keyStore, tempAccount, bep20ContractAddr, peggyAmount, bep2Symbol, bep20Owner, chainId)
 		},
 	}
 	cmd.Flags().String(constValue.KeystorePath, constValue.BindKeystore, "keystore path")
 	cmd.Flags().String(constValue.BEP20ContractAddr, "", "bep20 contract address")
-	cmd.Flags().String(constValue.BEP20Owner, "", "bep20 token owner")
-	cmd.Flags().String(constValue.BEP2Symbol, "", "bep2 token symbol")
-	cmd.Flags().String(constValue.PeggyAmount, "", "peggy amount, which is identical to the peggy amount in bind transaction")
+	cmd.Flags().String(constValue.BEP20Owner, "", "bep20 token owner, default to the owner in the config")
+	cmd.Flags().String(constValue.BEP2Symbol, "", "bep2 token symbol, default to the bep2_symbol in the config")
+	cmd.Flags().String(constValue.PeggyAmount, "", "peggy amount, which is identical to the peggy amount in bind transaction, in whole BEP2 tokens like \"1.5M\" or \"1000 ABC\", or in BEP2 units with 8 decimals like \"100000000 wei\", default to the peggy_amount in the config")
+	cmd.Flags().String(constValue.ConfigPath, "", "config file path, take the defaults of the flags above from its contract")
+	cmd.Flags().String(constValue.Contract, "", "name of the contract in the config, when the config has several")
+	cmd.Flags().Bool(constValue.Yes, false, "skip the typed confirmation, for scripted use")
+	cmd.Flags().Bool(constValue.ConfirmTestnet, false, "ask for the typed confirmation on testnet as well")
+	cmd.Flags().Bool(constValue.Simulate, false, "replay the planned transactions with eth_call and report the result, without sending them")
+	cmd.Flags().Bool(constValue.DryRun, false, "print the planned transactions and exit without signing")
+	cmd.Flags().String(constValue.Output, constValue.OutputText, "dry run output format, text or json")
 	return cmd
 }
 
@@ -346,12 +529,15 @@ func DeployBEP20ContractTransferTotalSupplyAndOwnershipCmd() *cobra.Command {
 		Use:   "deployBEP20ContractTransferTotalSupplyAndOwnership --config-path {config path}",
 		Short: "Deploy a bep20 contract, and transfer total balance and ownership to the account(specified in the config file)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ethClient, chainId, err := getEnv()
+			deployConfig, config, err := readContractConfig()
+			if err != nil {
+				return err
+			}
+			gas, err := configDeployGas(deployConfig)
 			if err != nil {
 				return err
 			}
-			configPath := viper.GetString(constValue.ConfigPath)
-			config, err := config.ReadConfigData(configPath)
+			ethClient, chainId, err := getEnv()
 			if err != nil {
 				return err
 			}
@@ -360,20 +546,65 @@ func DeployBEP20ContractTransferTotalSupplyAndOwnershipCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			bep20Owner := viper.GetString(constValue.BEP20Owner)
-			if utils.ValidateBSCAddr(bep20Owner) != nil {
+			viper.SetDefault(constValue.BEP20Owner, config.Owner)
+			bep20Owner, err := recipientAddrFlag(ethClient, constValue.BEP20Owner)
+			if err != nil {
 				return err
 			}
-			contractAddr, err := DeployContractFromTempAccount(ethClient, keyStore, tempAccount, config.ContractData, chainId)
+			if code, err := hex.DecodeString(config.ContractData); err == nil {
+				if report := utils.AnalyzeBytecode(code); len(report.MissingMethods) != 0 {
+					fmt.Println(fmt.Sprintf("Warning: the contract byte code misses BEP20 methods: %s", strings.Join(report.MissingMethods, ", ")))
+				}
+			}
+			if err := checkDryRunFlags(); err != nil {
+				return err
+			}
+			if viper.GetBool(constValue.DryRun) {
+				plan, err := newDryRunPlan(ethClient, cmd.Name(), tempAccount.Address, chainId)
+				if err != nil {
+					return err
+				}
+				if err := planDeployAndTransfer(plan, config.ContractData, gas, bep20Owner); err != nil {
+					return err
+				}
+				return printDryRunPlan(plan)
+			}
+			if viper.GetBool(constValue.Simulate) {
+				rpcUrl, _, err := getRPCUrl()
+				if err != nil {
+					return err
+				}
+				steps, err := SimulateDeployAndTransfer(rpcUrl, ethClient, tempAccount.Address, config.ContractData, bep20Owner)
+				if err != nil {
+					return err
+				}
+				return printSimulation(steps)
+			}
+			err = confirmOperation(chainId, func() (*confirmSummary, error) {
+				return deployAndTransferSummary(ethClient, tempAccount.Address, bep20Owner)
+			})
 			if err != nil {
 				return err
 			}
-			return TransferTokenAndOwnership(ethClient, keyStore, tempAccount, common.HexToAddress(bep20Owner), contractAddr, chainId)
+			deployment, err := DeployContractFromTempAccount(ethClient, keyStore, tempAccount, config.ContractData, gas, chainId)
+			if err != nil {
+				return err
+			}
+			if err := recordContractDeployment(deployment, config); err != nil {
+				return err
+			}
+			return TransferTokenAndOwnership(ethClient, keyStore, tempAccount, bep20Owner, deployment.Address, chainId)
 		},
 	}
 	cmd.Flags().String(constValue.KeystorePath, constValue.BindKeystore, "keystore path")
 	cmd.Flags().String(constValue.ConfigPath, "", "config file path")
-	cmd.Flags().String(constValue.BEP20Owner, "", "bep20 contract address")
+	cmd.Flags().String(constValue.Contract, "", "name of the contract to deploy, when the config has several")
+	cmd.Flags().String(constValue.BEP20Owner, "", "bep20 token owner, default to the owner in the config")
+	cmd.Flags().Bool(constValue.Yes, false, "skip the typed confirmation, for scripted use")
+	cmd.Flags().Bool(constValue.ConfirmTestnet, false, "ask for the typed confirmation on testnet as well")
+	cmd.Flags().Bool(constValue.Simulate, false, "replay the planned transactions with eth_call and report the result, without sending them")
+	cmd.Flags().Bool(constValue.DryRun, false, "print the planned transactions and exit without signing")
+	cmd.Flags().String(constValue.Output, constValue.OutputText, "dry run output format, text or json")
 	return cmd
 }
 
@@ -382,6 +613,9 @@ func ApproveBindFromLedgerCmd() *cobra.Command {
 		Use:   "approveBindFromLedger",
 		Short: "Call tokenManager contract to approve bind with a bep2 token. Users should firstly send bind transaction on Binance Chain, and wait for 30 second",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bindConfigDefaults(false); err != nil {
+				return err
+			}
 			if viper.GetString(constValue.NetworkType) == constValue.TestNet && viper.GetString(constValue.PeggyAmount) == "" {
 				return fmt.Errorf("on testnet, you must specify peggy amount manually")
 			}
@@ -392,9 +626,9 @@ func ApproveBindFromLedgerCmd() *cobra.Command {
 
 			ledgerAccountIndex := viper.GetInt32(constValue.LedgerAccountIndex)
 
-			bep20ContractAddr := viper.GetString(constValue.BEP20ContractAddr)
-			if !strings.HasPrefix(bep20ContractAddr, "0x") || len(bep20ContractAddr) != constValue.BSCAddrLength {
-				return fmt.Errorf("Invalid bep20 contract address")
+			bep20ContractAddr, err := contractAddrFlag(ethClient, constValue.BEP20ContractAddr)
+			if err != nil {
+				return err
 			}
 			bep2Symbol := viper.GetString(constValue.BEP2Symbol)
 			if len(bep2Symbol) == 0 {
@@ -406,16 +640,39 @@ func ApproveBindFromLedgerCmd() *cobra.Command {
 			}
 			var peggyAmount *big.Int
 			if viper.GetString(constValue.NetworkType) == constValue.TestNet {
-				peggyAmount = big.NewInt(0)
-				peggyAmount.SetString(viper.GetString(constValue.PeggyAmount), 10)
+				peggyAmount, err = peggyAmountFlag(bep2Symbol)
+				if err != nil {
+					return err
+				}
+			}
+			if err := checkDryRunFlags(); err != nil {
+				return err
+			}
+			if viper.GetBool(constValue.DryRun) {
+				plan, err := newDryRunPlan(ethClient, cmd.Name(), ledgerAccount.Address, chainId)
+				if err != nil {
+					return err
+				}
+				if err := planApproveBind(plan, ethClient, bep20ContractAddr, bep2Symbol, peggyAmount, nil, nil); err != nil {
+					return err
+				}
+				return printDryRunPlan(plan)
 			}
-			return ApproveBind(ethClient, ledgerWallet, ledgerAccount, bep2Symbol, common.HexToAddress(bep20ContractAddr), peggyAmount, chainId)
+			if viper.GetBool(constValue.Simulate) {
+				return simulateApproveBindCmd(ethClient, ledgerAccount.Address, bep20ContractAddr, bep2Symbol, peggyAmount, common.Address{}, false)
+			}
+			return ApproveBind(ethClient, ledgerWallet, ledgerAccount, bep2Symbol, bep20ContractAddr, peggyAmount, chainId)
 		},
 	}
 	cmd.Flags().String(constValue.BEP20ContractAddr, "", "bep20 contract address")
-	cmd.Flags().String(constValue.BEP2Symbol, "", "bep2 token symbol")
+	cmd.Flags().String(constValue.BEP2Symbol, "", "bep2 token symbol, default to the bep2_symbol in the config")
 	cmd.Flags().Int64(constValue.LedgerAccountIndex, 0, "ledger account index")
-	cmd.Flags().String(constValue.PeggyAmount, "", "peggy amount, which is identical to the peggy amount in bind transaction")
+	cmd.Flags().String(constValue.PeggyAmount, "", "peggy amount, which is identical to the peggy amount in bind transaction, in whole BEP2 tokens like \"1.5M\" or \"1000 ABC\", or in BEP2 units with 8 decimals like \"100000000 wei\", default to the peggy_amount in the config")
+	cmd.Flags().String(constValue.ConfigPath, "", "config file path, take the bep2 symbol and the peggy amount from its contract")
+	cmd.Flags().String(constValue.Contract, "", "name of the contract in the config, when the config has several")
+	cmd.Flags().Bool(constValue.Simulate, false, "replay the planned transactions with eth_call and report the result, without sending them")
+	cmd.Flags().Bool(constValue.DryRun, false, "print the planned transactions and exit without signing")
+	cmd.Flags().String(constValue.Output, constValue.OutputText, "dry run output format, text or json")
 	return cmd
 }
 
@@ -428,40 +685,61 @@ func RefundRestBNBCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			recipientStr := viper.GetString(constValue.Recipient)
-			if !strings.HasPrefix(recipientStr, "0x") || len(recipientStr) != constValue.BSCAddrLength {
-				return fmt.Errorf("Invalid refund address")
+			recipient, err := recipientAddrFlag(ethClient, constValue.Recipient)
+			if err != nil {
+				return err
 			}
 			keystorePath := viper.GetString(constValue.KeystorePath)
 			keyStore, tempAccount, err := generateOrGetTempAccount(keystorePath, chainId)
 			if err != nil {
 				return err
 			}
-			return RefundRestBNB(ethClient, keyStore, tempAccount, common.HexToAddress(recipientStr), chainId)
+			if viper.GetBool(constValue.DryRun) {
+				plan, err := newDryRunPlan(ethClient, cmd.Name(), tempAccount.Address, chainId)
+				if err != nil {
+					return err
+				}
+				if err := planRefundRestBNB(plan, ethClient, recipient); err != nil {
+					return err
+				}
+				return printDryRunPlan(plan)
+			}
+			err = confirmOperation(chainId, func() (*confirmSummary, error) {
+				return refundSummary(ethClient, tempAccount.Address, recipient)
+			})
+			if err != nil {
+				return err
+			}
+			return RefundRestBNB(ethClient, keyStore, tempAccount, recipient, chainId)
 		},
 	}
 	cmd.Flags().String(constValue.KeystorePath, constValue.BindKeystore, "keystore path")
 	cmd.Flags().String(constValue.Recipient, "", "recipient, bsc address")
+	cmd.Flags().Bool(constValue.Yes, false, "skip the typed confirmation, for scripted use")
+	cmd.Flags().Bool(constValue.ConfirmTestnet, false, "ask for the typed confirmation on testnet as well")
+	cmd.Flags().Bool(constValue.DryRun, false, "print the planned transactions and exit without signing")
+	cmd.Flags().String(constValue.Output, constValue.OutputText, "dry run output format, text or json")
 	return cmd
 }
 
-func DeployContractFromTempAccount(ethClient *ethclient.Client, keyStore *keystore.KeyStore, tempAccount accounts.Account, contractByteCodeStr string, chainId *big.Int) (common.Address, error) {
+// DeployContractFromTempAccount deploys contractByteCodeStr and returns its deployment record, without name.
+func DeployContractFromTempAccount(ethClient *ethclient.Client, keyStore *keystore.KeyStore, tempAccount accounts.Account, contractByteCodeStr string, gas deployGas, chainId *big.Int) (*utils.Deployment, error) {
 	contractByteCode, err := hex.DecodeString(contractByteCodeStr)
 	if err != nil {
-		return common.Address{}, err
+		return nil, err
 	}
-	txHash, err := utils.DeployContract(ethClient, keyStore, tempAccount, contractByteCode, chainId)
+	txHash, err := utils.DeployContract(ethClient, keyStore, tempAccount, contractByteCode, gas.Limit, gas.Price, chainId)
 	if err != nil {
-		return common.Address{}, err
+		return nil, err
 	}
 	time.Sleep(10 * time.Second)
 
 	txRecipient, err := ethClient.TransactionReceipt(context.Background(), txHash)
 	if err != nil {
-		return common.Address{}, err
+		return nil, err
 	}
 	contractAddr := txRecipient.ContractAddress
-	return contractAddr, nil
+	return utils.NewDeployment(ethClient, viper.GetString(constValue.NetworkType), chainId, tempAccount.Address, txHash, contractAddr, contractByteCode)
 }
 
 func ApproveBindAndTransferOwnershipAndRestBalanceBackToLedgerAccount(ethClient *ethclient.Client, keyStore *keystore.KeyStore, tempAccount accounts.Account, bep20ContractAddr common.Address, peggyAmount *big.Int, bep2Symbol string, bep20Owner common.Address, chainId *big.Int) error {
@@ -473,26 +751,9 @@ func ApproveBindAndTransferOwnershipAndRestBalanceBackToLedgerAccount(ethClient
 	if err != nil {
 		return err
 	}
-	var lockAmount *big.Int
-	if peggyAmount == nil {
-		lockAmount, err = tokenManagerInstance.QueryRequiredLockAmountForBind(utils.GetCallOpts(), bep2Symbol)
-		if err != nil {
-			return err
-		}
-	} else {
-		totalSupply, err := bep20Instance.TotalSupply(utils.GetCallOpts())
-		if err != nil {
-			return err
-		}
-		decimals, err := bep20Instance.Decimals(utils.GetCallOpts())
-		if err != nil {
-			return err
-		}
-
-		lockAmount = big.NewInt(1).Sub(totalSupply, utils.ConvertToBEP20Amount(peggyAmount, decimals.Int64()))
-		if lockAmount.Cmp(big.NewInt(0)) < 0 {
-			return fmt.Errorf("peggy amount is large than total supply")
-		}
+	lockAmount, err := getLockAmount(ethClient, bep20ContractAddr, bep2Symbol, peggyAmount)
+	if err != nil {
+		return err
 	}
 
 	fmt.Println(fmt.Sprintf("Approve %s:%s to TokenManager from %s", lockAmount.String(), bep2Symbol, tempAccount.Address.String()))
@@ -527,7 +788,12 @@ func ApproveBindAndTransferOwnershipAndRestBalanceBackToLedgerAccount(ethClient
 	}
 	fmt.Println("Track approveBind Tx status")
 	if approveBindTxRecipient.Status != 1 {
-		fmt.Println("Approve Bind is failed")
+		rpcUrl, _, err := getRPCUrl()
+		if err != nil {
+			return err
+		}
+		reason := utils.ReplayFailedTx(rpcUrl, approveBindTx, approveBindTxRecipient.BlockNumber, chainId)
+		fmt.Println(fmt.Sprintf("Approve Bind is failed: %s", reason))
 		rejectBindTx, err := tokenManagerInstance.RejectBind(utils.GetTransactor(ethClient, keyStore, tempAccount, miniRelayerFee), bep20ContractAddr, bep2Symbol)
 		if err != nil {
 			return err
@@ -540,7 +806,7 @@ func ApproveBindAndTransferOwnershipAndRestBalanceBackToLedgerAccount(ethClient
 			return err
 		}
 		fmt.Println(fmt.Sprintf("reject bind tx recipient status %d", rejectBindTxRecipient.Status))
-		return nil
+		return fmt.Errorf("approveBind transaction %s failed: %s", approveBindTx.Hash().String(), reason)
 	} else {
 		fmt.Println("Approve Bind is successful")
 	}
@@ -569,39 +835,19 @@ func ApproveBindAndTransferOwnershipAndRestBalanceBackToLedgerAccount(ethClient
 		return err
 	}
 	utils.PrintTxExplorerUrl("Transfer ownership txHash", transferOwnerShipTxHash.Hash().String(), chainId)
+	utils.Sleep(10)
+	if err := checkTxReceipt(ethClient, transferOwnerShipTxHash, chainId); err != nil {
+		return err
+	}
 	fmt.Println("--------------------------------------------------------------------------------------------------------------------------------")
 	return nil
 }
 
 func ApproveBind(ethClient *ethclient.Client, ledgerWallet accounts.Wallet, ledgerAccount accounts.Account, bep2Symbol string, bep20ContractAddr common.Address, peggyAmount *big.Int, chainId *big.Int) error {
-	tokenManagerInstance, err := tokenmanager.NewTokenmanager(constValue.TokenManagerContractAddr, ethClient)
+	lockAmount, err := getLockAmount(ethClient, bep20ContractAddr, bep2Symbol, peggyAmount)
 	if err != nil {
 		return err
 	}
-	var lockAmount *big.Int
-	if peggyAmount == nil {
-		lockAmount, err = tokenManagerInstance.QueryRequiredLockAmountForBind(utils.GetCallOpts(), bep2Symbol)
-		if err != nil {
-			return err
-		}
-	} else {
-		bep20Instance, err := bep20.NewBep20(bep20ContractAddr, ethClient)
-		if err != nil {
-			return err
-		}
-		totalSupply, err := bep20Instance.TotalSupply(utils.GetCallOpts())
-		if err != nil {
-			return err
-		}
-		decimals, err := bep20Instance.Decimals(utils.GetCallOpts())
-		if err != nil {
-			return err
-		}
-		lockAmount = big.NewInt(1).Sub(totalSupply, utils.ConvertToBEP20Amount(peggyAmount, decimals.Int64()))
-		if lockAmount.Cmp(big.NewInt(0)) < 0 {
-			return fmt.Errorf("peggy amount is large than total supply")
-		}
-	}
 	fmt.Println(fmt.Sprintf("Approve %s to TokenManager from %s", lockAmount.String(), ledgerAccount.Address.String()))
 	bep20ABI, _ := abi.JSON(strings.NewReader(bep20.Bep20ABI))
 	approveTxData, err := bep20ABI.Pack("approve", constValue.TokenManagerContractAddr, lockAmount)
@@ -616,6 +862,9 @@ func ApproveBind(ethClient *ethclient.Client, ledgerWallet accounts.Wallet, ledg
 	utils.PrintTxExplorerUrl("Approve token to tokenManagerContractAddr txHash", approveTx.Hash().String(), chainId)
 
 	utils.Sleep(10)
+	if err := checkTxReceipt(ethClient, approveTx, chainId); err != nil {
+		return err
+	}
 
 	tokenhubInstance, err := tokenhub.NewTokenhub(constValue.TokenHubContractAddr, ethClient)
 	if err != nil {
@@ -638,198 +887,151 @@ func ApproveBind(ethClient *ethclient.Client, ledgerWallet accounts.Wallet, ledg
 		return err
 	}
 	utils.PrintTxExplorerUrl("ApproveBind txHash", approveBindTx.Hash().String(), chainId)
+	utils.Sleep(10)
+	if err := checkTxReceipt(ethClient, approveBindTx, chainId); err != nil {
+		return err
+	}
 	fmt.Println("--------------------------------------------------------------------------------------------------------------------------------")
 	return nil
 }
 
-func TransferTokenAndOwnership(ethClient *ethclient.Client, keyStore *keystore.KeyStore, tempAccount accounts.Account, tokenOwner common.Address, bep20ContractAddr common.Address, chainId *big.Int) error {
-	bep20Instance, err := bep20.NewBep20(bep20ContractAddr, ethClient)
-	if err != nil {
-		return err
-	}
-	totalSupply, err := bep20Instance.TotalSupply(utils.GetCallOpts())
+// checkTxReceipt returns an error with the decoded revert reason if tx failed.
+func checkTxReceipt(ethClient *ethclient.Client, tx *ethtypes.Transaction, chainId *big.Int) error {
+	rpcUrl, _, err := getRPCUrl()
 	if err != nil {
 		return err
 	}
-	fmt.Println(fmt.Sprintf("Total Supply %s", totalSupply.String()))
+	return utils.CheckTxReceipt(rpcUrl, ethClient, tx, chainId)
+}
 
-	fmt.Println(fmt.Sprintf("Transfer %s token to %s", totalSupply.String(), tokenOwner.String()))
-	transferTxHash, err := bep20Instance.Transfer(utils.GetTransactor(ethClient, keyStore, tempAccount, big.NewInt(0)), tokenOwner, totalSupply)
-	if err != nil {
-		return err
+// printNotice prints msg unless the command writes json, which has to stay parseable.
+func printNotice(msg string) {
+	if viper.GetString(constValue.Output) != constValue.OutputJSON {
+		fmt.Println(msg)
 	}
-	utils.PrintTxExplorerUrl("Transfer token txHash", transferTxHash.Hash().String(), chainId)
-
-	utils.Sleep(10)
+}
 
-	ownershipInstance, err := ownable.NewOwnable(bep20ContractAddr, ethClient)
-	if err != nil {
-		return err
+// bareAmountError rejects input, the value of an amount flag, when it is a bare integer. Earlier releases read such a
+// value in BEP2 units of 8 decimals, while amounts now count whole tokens, so a bare integer must name its unit.
+// rawDecimals are the decimals of the wei unit of the flag, and the hint gives the legacy value in both units.
+func bareAmountError(flag, input, symbol string, rawDecimals int64) error {
+	input = strings.TrimSpace(input)
+	if len(input) == 0 || strings.TrimLeft(input, "0123456789") != "" {
+		return nil
 	}
-	fmt.Println(fmt.Sprintf("Transfer ownership to %s", tokenOwner.String()))
-	transferOwnerShipTxHash, err := ownershipInstance.TransferOwnership(utils.GetTransactor(ethClient, keyStore, tempAccount, big.NewInt(0)), tokenOwner)
+	legacy, err := decimal.NewFromString(input)
 	if err != nil {
-		return err
+		return nil
 	}
-	utils.PrintTxExplorerUrl("Transfer ownership txHash", transferOwnerShipTxHash.Hash().String(), chainId)
-	fmt.Println("--------------------------------------------------------------------------------------------------------------------------------")
-	return nil
+	whole := legacy.Shift(-constValue.BcDecimals)
+	hint := fmt.Sprintf("\"%s %s\" in whole tokens", whole.String(), symbol)
+	if raw := whole.Shift(int32(rawDecimals)); raw.Equal(raw.Truncate(0)) {
+		hint += fmt.Sprintf(" or \"%s %s\" in units of %d decimals", raw.String(), utils.RawUnit, rawDecimals)
+	}
+	return fmt.Errorf("--%s %s: a bare integer used to count BEP2 units of %d decimals and now needs a unit, for the same amount pass %s",
+		flag, input, constValue.BcDecimals, hint)
 }
 
-func RefundRestBNB(ethClient *ethclient.Client, keyStore *keystore.KeyStore, tempAccount accounts.Account, refundAddr common.Address, chainId *big.Int) error {
-	utils.Sleep(10)
-	txHash, err := utils.SendAllRestBNB(ethClient, keyStore, tempAccount, refundAddr, chainId)
+// peggyAmountFlag parses --peggy-amount into BEP2 units with 8 decimals and echoes it. It returns nil when the flag is
+// not set.
+func peggyAmountFlag(bep2Symbol string) (*big.Int, error) {
+	peggyAmountStr := viper.GetString(constValue.PeggyAmount)
+	if len(peggyAmountStr) == 0 {
+		return nil, nil
+	}
+	bep20Symbol := strings.Split(bep2Symbol, "-")[0]
+	if err := bareAmountError(constValue.PeggyAmount, peggyAmountStr, bep20Symbol, constValue.BcDecimals); err != nil {
+		return nil, err
+	}
+	amount, err := utils.ParseAmount(peggyAmountStr, constValue.BcDecimals, bep2Symbol, bep20Symbol)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("--%s: %s", constValue.PeggyAmount, err.Error())
 	}
-	utils.PrintTxExplorerUrl("Refund txHash", txHash.String(), chainId)
-	fmt.Println("--------------------------------------------------------------------------------------------------------------------------------")
-	return nil
+	printNotice(fmt.Sprintf("Peggy amount: %s", amount.String()))
+	return amount.Raw, nil
 }
 
-func PreCheckCmd() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "preCheck",
-		Short: "Verify whether the BEP2 and BEP20 can be bind-ed or not, and give suggestions based on different cases",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if viper.GetString(constValue.NetworkType) == constValue.TestNet {
-				return fmt.Errorf("this command only works on mainnet")
-			}
-			ethClient, _, err := getEnv()
-			if err != nil {
-				return err
-			}
-
-			bep20ContractAddr := viper.GetString(constValue.BEP20ContractAddr)
-			if !strings.HasPrefix(bep20ContractAddr, "0x") || len(bep20ContractAddr) != constValue.BSCAddrLength {
-				return fmt.Errorf("invalid bep20 contract address")
-			}
-			bep2Symbol := viper.GetString(constValue.BEP2Symbol)
-			if len(bep2Symbol) == 0 {
-				return fmt.Errorf("missing bep2 symbol")
-			}
-
-			return PreCheckBind(ethClient, bep2Symbol, common.HexToAddress(bep20ContractAddr))
-		},
+// getLockAmount returns the amount of BEP20 tokens approveBind locks. Without peggyAmount it is read from the pending
+// bind package on TokenManager, otherwise it is the total supply minus peggyAmount scaled to the BEP20 decimals.
+func getLockAmount(ethClient *ethclient.Client, bep20ContractAddr common.Address, bep2Symbol string, peggyAmount *big.Int) (*big.Int, error) {
+	if peggyAmount == nil {
+		tokenManagerInstance, err := tokenmanager.NewTokenmanager(constValue.TokenManagerContractAddr, ethClient)
+		if err != nil {
+			return nil, err
+		}
+		return tokenManagerInstance.QueryRequiredLockAmountForBind(utils.GetCallOpts(), bep2Symbol)
 	}
-	cmd.Flags().String(constValue.BEP20ContractAddr, "", "bep20 contract address")
-	cmd.Flags().String(constValue.BEP2Symbol, "", "bep2 token symbol")
-	return cmd
-}
-
-func PreCheckBind(ethClient *ethclient.Client, bep2Symbol string, bep20ContractAddr common.Address) error {
-	bep2Instance, err := getBep2Token(bep2Symbol)
+	bep20Instance, err := bep20.NewBep20(bep20ContractAddr, ethClient)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if bep2Instance.ContractAddress != nil {
-		return errors.Errorf("the BEP2 %s is already bind to %s", bep2Symbol, bep2Instance.ContractAddress)
+	totalSupply, err := bep20Instance.TotalSupply(utils.GetCallOpts())
+	if err != nil {
+		return nil, err
 	}
-
-	bep20Instance, err := bep20.NewBep20(bep20ContractAddr, ethClient)
+	decimals, err := bep20Instance.Decimals(utils.GetCallOpts())
 	if err != nil {
-		return err
+		return nil, err
 	}
-	bep20Symbol, err := bep20Instance.Symbol(utils.GetCallOpts())
+	bep20PeggyAmount, err := utils.ConvertToBEP20Amount(peggyAmount, decimals.Int64(), utils.RoundExact)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("invalid peggy amount: %s", err.Error())
 	}
-	bep20TotalSupply, err := bep20Instance.TotalSupply(utils.GetCallOpts())
+	lockAmount := big.NewInt(1).Sub(totalSupply, bep20PeggyAmount)
+	if lockAmount.Cmp(big.NewInt(0)) < 0 {
+		return nil, fmt.Errorf("peggy amount is large than total supply")
+	}
+	return lockAmount, nil
+}
+
+func TransferTokenAndOwnership(ethClient *ethclient.Client, keyStore *keystore.KeyStore, tempAccount accounts.Account, tokenOwner common.Address, bep20ContractAddr common.Address, chainId *big.Int) error {
+	bep20Instance, err := bep20.NewBep20(bep20ContractAddr, ethClient)
 	if err != nil {
 		return err
 	}
-	bep20Decimals, err := bep20Instance.Decimals(utils.GetCallOpts())
+	totalSupply, err := bep20Instance.TotalSupply(utils.GetCallOpts())
 	if err != nil {
 		return err
 	}
+	fmt.Println(fmt.Sprintf("Total Supply %s", totalSupply.String()))
 
-	hasError := false
-	fmt.Printf("\n1. Checking BEP20 symbol length: %s \n", bep20Symbol)
-	if len(bep20Symbol) < 2 || len(bep20Symbol) > 8 {
-		hasError = true
-		fmt.Printf("Cannot bind: BEP20 symbol length should be between 2 and 8\n")
-		fmt.Printf("Suggestion: please swap tokens offchain, e.g., " +
-			"through CEXs (if your tokens are listed on Binance.com, you can ask your users deposit their tokens to it then do the exchange)\n")
-	} else {
-		fmt.Println("Pass")
-	}
-
-	fmt.Printf("\n2. Checking symbols match or not, BEP2: %s, BEP20: %s \n", bep2Symbol, bep20Symbol)
-	if !strings.HasPrefix(bep2Symbol, bep20Symbol) || bep2Symbol[len(bep20Symbol)] != '-' {
-		hasError = true
-		fmt.Printf("Cannot bind: BEP2 and BEP20 sybmols do not match\n")
-		fmt.Printf("Suggestion: please swap tokens offchain, e.g., " +
-			"through CEXs (if your tokens are listed on Binance.com, you can ask your users deposit their tokens to it then do the exchange)\n")
-	} else {
-		fmt.Println("Pass")
+	fmt.Println(fmt.Sprintf("Transfer %s token to %s", totalSupply.String(), tokenOwner.String()))
+	transferTxHash, err := bep20Instance.Transfer(utils.GetTransactor(ethClient, keyStore, tempAccount, big.NewInt(0)), tokenOwner, totalSupply)
+	if err != nil {
+		return err
 	}
+	utils.PrintTxExplorerUrl("Transfer token txHash", transferTxHash.Hash().String(), chainId)
 
-	fmt.Printf("\n3. Checking BEP20 total supply exceeds the max BEP2 allowance or not: %s \n", bep20Symbol)
-	maxBep2Supply := utils.ConvertToBEP20Amount(big.NewInt(constValue.BcMaxSupply), bep20Decimals.Int64())
-	if bep20TotalSupply.Cmp(maxBep2Supply) > 0 {
-		hasError = true
-		fmt.Printf("Cannot bind: BEP20 total supply exceeds the max BEP2 allowance\n")
-		fmt.Println("BEP20 total supply: ", bep20TotalSupply)
-		fmt.Println("BEP2 max allowed total supply: ", maxBep2Supply)
-		fmt.Printf("Suggestion: please mange your BEP20 token total supply, e.g., burn tokens")
-	} else {
-		fmt.Println("Pass")
+	utils.Sleep(10)
+	if err := checkTxReceipt(ethClient, transferTxHash, chainId); err != nil {
+		return err
 	}
 
-	fmt.Printf("\n4. Checking BEP20 total supply and BEP2 total supply match or not \n")
-	amount, err := decimal.NewFromString(bep2Instance.TotalSupply)
+	ownershipInstance, err := ownable.NewOwnable(bep20ContractAddr, ethClient)
 	if err != nil {
 		return err
 	}
-	amount = amount.Mul(decimal.New(int64(1e8), 0))
-	bep2Supply := utils.ConvertToBEP20Amount(amount.BigInt(), bep20Decimals.Int64())
-	if bep20TotalSupply.Cmp(bep2Supply) != 0 {
-		hasError = true
-		fmt.Printf("Cannot bind: BEP20 total supply and BEP2 total supply do not match \n")
-		fmt.Println("BEP20 total supply: ", bep20TotalSupply)
-		fmt.Println("BEP2 total supply: ", bep2Supply)
-		fmt.Printf("Suggestion: please mange your BEP20/BEP2 token total supply, e.g., burn tokens, mint tokens, " +
-			"to make use they are equal using different decimals\n")
-	} else {
-		fmt.Println("Pass")
-	}
-
-	fmt.Printf("\n5. Checking BEP20 has getOwner function or not: %s \n", bep20Symbol)
-	_, err = bep20Instance.GetOwner(utils.GetCallOpts())
+	fmt.Println(fmt.Sprintf("Transfer ownership to %s", tokenOwner.String()))
+	transferOwnerShipTxHash, err := ownershipInstance.TransferOwnership(utils.GetTransactor(ethClient, keyStore, tempAccount, big.NewInt(0)), tokenOwner)
 	if err != nil {
-		hasError = true
-		fmt.Printf("Cannot bind: BEP20 does not implement getOwner funtion \n")
-		fmt.Printf("Suggestion: please upgrade your contract to follow BEP20 standards, i.e., adding getOwner function")
-	} else {
-		fmt.Println("Pass")
+		return err
 	}
-
-	if hasError {
-		fmt.Printf("\n The BEP2 and BEP20 cannot bind, please take actions according the suggestions \n")
-	} else {
-		fmt.Printf("\n You can bind the BEP2 and BEP20 tokens, please read README and take actions \n")
+	utils.PrintTxExplorerUrl("Transfer ownership txHash", transferOwnerShipTxHash.Hash().String(), chainId)
+	utils.Sleep(10)
+	if err := checkTxReceipt(ethClient, transferOwnerShipTxHash, chainId); err != nil {
+		return err
 	}
+	fmt.Println("--------------------------------------------------------------------------------------------------------------------------------")
 	return nil
 }
 
-func getBep2Token(symbol string) (*types.Bep2, error) {
-	myClient := &http.Client{Timeout: 10 * time.Second}
-	r, err := myClient.Get(constValue.BcMainnnetTokenUrl)
-	if err != nil {
-		return nil, err
-	}
-	defer r.Body.Close()
-
-	var tokens []*types.Bep2
-	err = json.NewDecoder(r.Body).Decode(&tokens)
+func RefundRestBNB(ethClient *ethclient.Client, keyStore *keystore.KeyStore, tempAccount accounts.Account, refundAddr common.Address, chainId *big.Int) error {
+	utils.Sleep(10)
+	txHash, err := utils.SendAllRestBNB(ethClient, keyStore, tempAccount, refundAddr, chainId)
 	if err != nil {
-		return nil, err
-	}
-
-	for _, token := range tokens {
-		if token.Symbol == symbol {
-			return token, err
-		}
+		return err
 	}
-	return nil, errors.Errorf("cannot find the BEP2 %s", symbol)
+	utils.PrintTxExplorerUrl("Refund txHash", txHash.String(), chainId)
+	fmt.Println("--------------------------------------------------------------------------------------------------------------------------------")
+	return nil
 }
@@ -0,0 +1,150 @@
+package command
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/binance-chain/token-bind-tool/config"
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+// deployGas is the gas limit and gas price of a contract deployment.
+type deployGas struct {
+	Limit uint64
+	Price *big.Int
+}
+
+func defaultDeployGas() deployGas {
+	return deployGas{Limit: constValue.DefaultGasLimit, Price: big.NewInt(constValue.DefaultGasPrice)}
+}
+
+// configDeployGas returns the gas settings of deployConfig, falling back to the defaults.
+func configDeployGas(deployConfig *config.DeployConfig) (deployGas, error) {
+	gas := defaultDeployGas()
+	if limit := deployConfig.GasLimit(); limit != 0 {
+		gas.Limit = limit
+	}
+	price, err := deployConfig.GasPrice()
+	if err != nil {
+		return deployGas{}, err
+	}
+	if price != nil {
+		gas.Price = price
+	}
+	return gas, nil
+}
+
+// readDeployConfig reads the deploy config in --config-path, or the artifact in --artifact linked with --link, and
+// checks that it targets --network-type.
+func readDeployConfig() (*config.DeployConfig, error) {
+	var deployConfig *config.DeployConfig
+	var err error
+	artifactPath := viper.GetString(constValue.Artifact)
+	links := viper.GetStringSlice(constValue.Link)
+	switch {
+	case len(artifactPath) != 0 && len(viper.GetString(constValue.ConfigPath)) != 0:
+		return nil, fmt.Errorf("--%s and --%s are exclusive, set artifact in the config", constValue.Artifact, constValue.ConfigPath)
+	case len(artifactPath) != 0:
+		deployConfig, err = config.ReadArtifactConfig(artifactPath, links)
+	case len(links) != 0:
+		return nil, fmt.Errorf("--%s needs --%s, set link in the config", constValue.Link, constValue.Artifact)
+	default:
+		deployConfig, err = config.ReadDeployConfig(viper.GetString(constValue.ConfigPath))
+	}
+	if err != nil {
+		return nil, err
+	}
+	networkType := viper.GetString(constValue.NetworkType)
+	if len(deployConfig.Network) != 0 && deployConfig.Network != networkType {
+		return nil, fmt.Errorf("the config targets %s, but --%s is %s", deployConfig.Network, constValue.NetworkType, networkType)
+	}
+	return deployConfig, nil
+}
+
+// readContractConfig reads the deploy config and selects the contract in --contract, which may be omitted when the
+// config has a single contract.
+func readContractConfig() (*config.DeployConfig, *config.Config, error) {
+	deployConfig, err := readDeployConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	contract, err := deployConfig.Contract(viper.GetString(constValue.Contract))
+	if err != nil {
+		return nil, nil, err
+	}
+	return deployConfig, contract, nil
+}
+
+// bindConfigDefaults seeds --bep2-symbol, --peggy-amount and, with withOwner, --bep20-owner with the contract in
+// --config-path, like the deploy commands take the owner. The config is only loaded, the bind does not need its byte
+// code. The peggy amount is seeded in BEP2 units, so that a bare number in the config keeps counting whole tokens.
+func bindConfigDefaults(withOwner bool) error {
+	configPath := viper.GetString(constValue.ConfigPath)
+	if len(configPath) == 0 {
+		return nil
+	}
+	deployConfig, err := config.LoadDeployConfig(configPath)
+	if err != nil {
+		return err
+	}
+	networkType := viper.GetString(constValue.NetworkType)
+	if len(deployConfig.Network) != 0 && deployConfig.Network != networkType {
+		return fmt.Errorf("the config targets %s, but --%s is %s", deployConfig.Network, constValue.NetworkType, networkType)
+	}
+	contract, err := deployConfig.Contract(viper.GetString(constValue.Contract))
+	if err != nil {
+		return err
+	}
+	viper.SetDefault(constValue.BEP2Symbol, contract.BEP2Symbol)
+	if withOwner {
+		viper.SetDefault(constValue.BEP20Owner, contract.Owner)
+	}
+	if len(contract.PeggyAmount) != 0 {
+		bep2Prefix := strings.Split(contract.BEP2Symbol, "-")[0]
+		peggyAmount, err := utils.ParseAmount(string(contract.PeggyAmount), constValue.BcDecimals, contract.BEP2Symbol, bep2Prefix)
+		if err != nil {
+			return fmt.Errorf("peggy_amount in %s: %s", configPath, err.Error())
+		}
+		viper.SetDefault(constValue.PeggyAmount, fmt.Sprintf("%s %s", peggyAmount.Raw.String(), utils.RawUnit))
+	}
+	return nil
+}
+
+func ConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Deploy config utilities",
+	}
+	cmd.AddCommand(ConfigValidateCmd())
+	return cmd
+}
+
+func ConfigValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate --config-path {config path}",
+		Short: "Validate a deploy config without touching the network or compiling sources",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := viper.GetString(constValue.ConfigPath)
+			if len(configPath) == 0 {
+				return fmt.Errorf("missing config path")
+			}
+			deployConfig, err := config.LoadDeployConfig(configPath)
+			if err != nil {
+				return err
+			}
+			network := deployConfig.Network
+			if len(network) == 0 {
+				network = "the network in --network-type"
+			}
+			fmt.Println(fmt.Sprintf("%s is valid: version %d, %d contracts, targets %s", configPath, deployConfig.Version, len(deployConfig.Contracts), network))
+			return nil
+		},
+	}
+	cmd.Flags().String(constValue.ConfigPath, "", "config file path")
+	return cmd
+}
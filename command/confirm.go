@@ -0,0 +1,171 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/viper"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/contracts/bep20"
+	"github.com/binance-chain/token-bind-tool/contracts/tokenhub"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+// confirmSuffixLen is how many trailing hex chars of the recipient must be typed to confirm.
+const confirmSuffixLen = 4
+
+// confirmSummary is what a value moving command shows before it asks for confirmation.
+type confirmSummary struct {
+	Title     string
+	Recipient common.Address
+	Lines     [][2]string
+}
+
+func (summary *confirmSummary) add(label, value string) {
+	summary.Lines = append(summary.Lines, [2]string{label, value})
+}
+
+// needConfirmation reports whether the typed confirmation is required. It is skipped with --yes, and on any chain but
+// mainnet unless --confirm-testnet is set. Mainnet is told by the chain id the rpc resolved to, not by --network-type,
+// since --network-type local may point at a mainnet node.
+func needConfirmation(chainId *big.Int) bool {
+	if viper.GetBool(constValue.Yes) {
+		return false
+	}
+	if chainId.Cmp(big.NewInt(constValue.MainnetChainID)) == 0 {
+		return true
+	}
+	return viper.GetBool(constValue.ConfirmTestnet)
+}
+
+// confirmOperation builds the summary, shows it and asks for the last hex chars of the recipient when a confirmation is
+// needed. The summary is only built then, since it reads from the chain.
+func confirmOperation(chainId *big.Int, buildSummary func() (*confirmSummary, error)) error {
+	if !needConfirmation(chainId) {
+		return nil
+	}
+	summary, err := buildSummary()
+	if err != nil {
+		return err
+	}
+	return promptConfirmation(summary, os.Stdin, os.Stdout)
+}
+
+func promptConfirmation(summary *confirmSummary, in io.Reader, out io.Writer) error {
+	fmt.Fprintln(out, summary.Title)
+	width := 0
+	for _, line := range summary.Lines {
+		if len(line[0]) > width {
+			width = len(line[0])
+		}
+	}
+	for _, line := range summary.Lines {
+		fmt.Fprintln(out, fmt.Sprintf("  %-*s  %s", width, line[0]+":", line[1]))
+	}
+	recipient := summary.Recipient.String()
+	expected := recipient[len(recipient)-confirmSuffixLen:]
+	fmt.Fprint(out, fmt.Sprintf("Type the last %d characters of the recipient %s to continue: ", confirmSuffixLen, recipient))
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if !strings.EqualFold(strings.TrimSpace(answer), expected) {
+		return fmt.Errorf("confirmation does not match, nothing is sent")
+	}
+	return nil
+}
+
+func maxGasFee(txCount int64) string {
+	fee := new(big.Int).Mul(big.NewInt(txCount*constValue.DefaultGasLimit), big.NewInt(constValue.DefaultGasPrice))
+	return fmt.Sprintf("up to %s BNB for %d transactions", utils.FormatUnits(fee, utils.BNBDecimals), txCount)
+}
+
+// approveBindSummary describes approveBindAndTransferOwnership: the lock amount, the relay fee, and the rest BEP20
+// balance and ownership which go to bep20Owner.
+func approveBindSummary(ethClient *ethclient.Client, sender, bep20ContractAddr common.Address, bep2Symbol string, peggyAmount *big.Int, bep20Owner common.Address) (*confirmSummary, error) {
+	bep20Instance, err := bep20.NewBep20(bep20ContractAddr, ethClient)
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := bep20Instance.Symbol(utils.GetCallOpts())
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := bep20Instance.Decimals(utils.GetCallOpts())
+	if err != nil {
+		return nil, err
+	}
+	balance, err := bep20Instance.BalanceOf(utils.GetCallOpts(), sender)
+	if err != nil {
+		return nil, err
+	}
+	lockAmount, err := getLockAmount(ethClient, bep20ContractAddr, bep2Symbol, peggyAmount)
+	if err != nil {
+		return nil, err
+	}
+	tokenhubInstance, err := tokenhub.NewTokenhub(constValue.TokenHubContractAddr, ethClient)
+	if err != nil {
+		return nil, err
+	}
+	relayFee, err := tokenhubInstance.GetMiniRelayFee(utils.GetCallOpts())
+	if err != nil {
+		return nil, err
+	}
+	rest := new(big.Int).Sub(balance, lockAmount)
+	if rest.Sign() < 0 {
+		rest = big.NewInt(0)
+	}
+
+	summary := &confirmSummary{Title: fmt.Sprintf("About to approve the bind and hand the BEP20 token over on %s", viper.GetString(constValue.NetworkType)), Recipient: bep20Owner}
+	summary.add("BEP20 contract", fmt.Sprintf("%s (%s, %d decimals)", bep20ContractAddr.String(), symbol, decimals.Int64()))
+	summary.add("BEP2 symbol", bep2Symbol)
+	summary.add("Lock amount", fmt.Sprintf("%s %s", utils.FormatUnits(lockAmount, decimals.Int64()), symbol))
+	summary.add("Relay fee", fmt.Sprintf("%s BNB", utils.FormatUnits(relayFee, utils.BNBDecimals)))
+	summary.add("Rest BEP20 balance to", fmt.Sprintf("%s, %s %s", bep20Owner.String(), utils.FormatUnits(rest, decimals.Int64()), symbol))
+	summary.add("New owner", bep20Owner.String())
+	summary.add("Gas fee", maxGasFee(4))
+	return summary, nil
+}
+
+// deployAndTransferSummary describes deployBEP20ContractTransferTotalSupplyAndOwnership. The total supply and the
+// ownership of the new contract go to bep20Owner.
+func deployAndTransferSummary(ethClient *ethclient.Client, sender, bep20Owner common.Address) (*confirmSummary, error) {
+	nonce, err := ethClient.PendingNonceAt(context.Background(), sender)
+	if err != nil {
+		return nil, err
+	}
+	summary := &confirmSummary{Title: fmt.Sprintf("About to deploy a BEP20 contract and hand it over on %s", viper.GetString(constValue.NetworkType)), Recipient: bep20Owner}
+	summary.add("BEP20 contract", fmt.Sprintf("%s (to be deployed)", crypto.CreateAddress(sender, nonce).String()))
+	summary.add("Total supply to", bep20Owner.String())
+	summary.add("New owner", bep20Owner.String())
+	summary.add("Gas fee", maxGasFee(3))
+	return summary, nil
+}
+
+// refundSummary describes refundRestBNB.
+func refundSummary(ethClient *ethclient.Client, sender, recipient common.Address) (*confirmSummary, error) {
+	balance, err := ethClient.BalanceAt(context.Background(), sender, nil)
+	if err != nil {
+		return nil, err
+	}
+	txFee := new(big.Int).Mul(big.NewInt(21000), big.NewInt(constValue.DefaultGasPrice))
+	amount := new(big.Int).Sub(balance, txFee)
+	if amount.Sign() < 0 {
+		amount = big.NewInt(0)
+	}
+	summary := &confirmSummary{Title: fmt.Sprintf("About to refund the rest BNB on %s", viper.GetString(constValue.NetworkType)), Recipient: recipient}
+	summary.add("From", sender.String())
+	summary.add("Recipient", recipient.String())
+	summary.add("Amount", fmt.Sprintf("%s BNB", utils.FormatUnits(amount, utils.BNBDecimals)))
+	summary.add("Gas fee", fmt.Sprintf("%s BNB", utils.FormatUnits(txFee, utils.BNBDecimals)))
+	return summary, nil
+}
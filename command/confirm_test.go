@@ -0,0 +1,48 @@
+package command
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+)
+
+func TestPromptConfirmation(t *testing.T) {
+	summary := &confirmSummary{Title: "About to refund", Recipient: common.HexToAddress("0x4E656459ed25bF986Eea1196Bc1B00665401645d")}
+	summary.add("Amount", "1.5 BNB")
+
+	var out bytes.Buffer
+	require.NoError(t, promptConfirmation(summary, strings.NewReader("645d\n"), &out))
+	require.Contains(t, out.String(), "Amount:  1.5 BNB")
+	require.NoError(t, promptConfirmation(summary, strings.NewReader("645D"), &out))
+	require.Error(t, promptConfirmation(summary, strings.NewReader("1645d\n"), &out))
+	require.Error(t, promptConfirmation(summary, strings.NewReader(""), &out))
+}
+
+func TestNeedConfirmation(t *testing.T) {
+	defer viper.Reset()
+	mainnet := big.NewInt(constValue.MainnetChainID)
+	testnet := big.NewInt(constValue.TestnetChainID)
+	viper.Set(constValue.NetworkType, constValue.Mainnet)
+	require.True(t, needConfirmation(mainnet))
+	viper.Set(constValue.Yes, true)
+	require.False(t, needConfirmation(mainnet))
+
+	viper.Set(constValue.Yes, false)
+	viper.Set(constValue.NetworkType, constValue.TestNet)
+	require.False(t, needConfirmation(testnet))
+	viper.Set(constValue.ConfirmTestnet, true)
+	require.True(t, needConfirmation(testnet))
+
+	// --network-type local with the rpc of a mainnet node still resolves to the mainnet chain id.
+	viper.Set(constValue.ConfirmTestnet, false)
+	viper.Set(constValue.NetworkType, constValue.Local)
+	require.True(t, needConfirmation(mainnet))
+	require.False(t, needConfirmation(big.NewInt(1337)))
+}
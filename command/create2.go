@@ -0,0 +1,212 @@
+package command
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/binance-chain/token-bind-tool/config"
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/contracts/bep20"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+// Create2Prediction is where a CREATE2 deployment through the factory creates a contract.
+type Create2Prediction struct {
+	Factory      common.Address `json:"factory"`
+	Salt         hexutil.Bytes  `json:"salt"`
+	InitCodeHash common.Hash    `json:"init_code_hash"`
+	Address      common.Address `json:"address"`
+}
+
+// saltFlag returns the CREATE2 salt in contractSalt, or else in --salt, and nil without salt.
+func saltFlag(contractSalt string) (*[32]byte, error) {
+	saltStr := contractSalt
+	if len(saltStr) == 0 {
+		saltStr = viper.GetString(constValue.Salt)
+	}
+	if len(saltStr) == 0 {
+		return nil, nil
+	}
+	salt, err := utils.ParseSalt(saltStr)
+	if err != nil {
+		return nil, fmt.Errorf("--%s: %s", constValue.Salt, err.Error())
+	}
+	return &salt, nil
+}
+
+// requireCreate2Owner refuses a CREATE2 deployment of contract unless its constructor takes an address argument, the
+// owner. The constructor runs with the factory as msg.sender, so a contract which mints the supply to, or makes the
+// owner of, msg.sender would leave both with the factory.
+func requireCreate2Owner(contract *config.Config) error {
+	name := contract.Name
+	if len(name) == 0 {
+		name = "the contract"
+	}
+	if len(contract.ContractABI) == 0 {
+		return fmt.Errorf("can not deploy %s with CREATE2 without its abi, the owner must be a constructor argument since the constructor runs with the factory as msg.sender", name)
+	}
+	contractABI, err := abi.JSON(strings.NewReader(contract.ContractABI))
+	if err != nil {
+		return err
+	}
+	for _, input := range contractABI.Constructor.Inputs {
+		if input.Type.T == abi.AddressTy {
+			return nil
+		}
+	}
+	return fmt.Errorf("can not deploy %s with CREATE2, its constructor takes no owner address and the constructor runs with the factory as msg.sender, which would own the contract and the supply", name)
+}
+
+// checkCreate2Holdings fails when the contract created by factory at contractAddr is owned by the factory, or when the
+// factory holds its tokens. Contracts without getOwner() or balanceOf() are not checked for them.
+func checkCreate2Holdings(ethClient *ethclient.Client, factory, contractAddr common.Address) error {
+	bep20Instance, err := bep20.NewBep20(contractAddr, ethClient)
+	if err != nil {
+		return err
+	}
+	if owner, err := bep20Instance.GetOwner(utils.GetCallOpts()); err == nil && owner == factory {
+		return fmt.Errorf("the contract %s is owned by the CREATE2 factory %s, nobody can administer it, deploy again with the owner as a constructor argument", contractAddr.String(), factory.String())
+	}
+	if balance, err := bep20Instance.BalanceOf(utils.GetCallOpts(), factory); err == nil && balance.Sign() > 0 {
+		return fmt.Errorf("the CREATE2 factory %s holds %s tokens of the contract %s, which can not be moved, deploy again with the owner as a constructor argument", factory.String(), balance.String(), contractAddr.String())
+	}
+	return nil
+}
+
+// create2FactoryFlag returns the factory in --create2-factory, default to the deterministic deployment proxy.
+func create2FactoryFlag() (common.Address, error) {
+	if len(viper.GetString(constValue.Create2Factory)) == 0 {
+		return utils.Create2FactoryAddr, nil
+	}
+	return addrFlag(constValue.Create2Factory)
+}
+
+// predictCreate2 computes where factory creates contractByteCodeStr with salt.
+func predictCreate2(factory common.Address, salt [32]byte, contractByteCodeStr string) (*Create2Prediction, error) {
+	initCode, err := hex.DecodeString(contractByteCodeStr)
+	if err != nil {
+		return nil, err
+	}
+	return &Create2Prediction{
+		Factory:      factory,
+		Salt:         salt[:],
+		InitCodeHash: crypto.Keccak256Hash(initCode),
+		Address:      utils.PredictCreate2Address(factory, salt, initCode),
+	}, nil
+}
+
+// planCreate2Deploy adds the call to factory which creates contractByteCodeStr with salt and returns the address the
+// contract will be created at.
+func planCreate2Deploy(plan *DryRunPlan, factory common.Address, salt [32]byte, contractByteCodeStr string, gas deployGas) (common.Address, error) {
+	initCode, err := hex.DecodeString(contractByteCodeStr)
+	if err != nil {
+		return common.Address{}, err
+	}
+	contractAddr := utils.PredictCreate2Address(factory, salt, initCode)
+	deployTx := plan.add("deploy contract with CREATE2", &factory, big.NewInt(0), utils.Create2Calldata(salt, initCode), gas.Limit)
+	deployTx.GasPrice = (*hexutil.Big)(gas.Price)
+	deployTx.Call, err = utils.DecodeCreation(initCode)
+	deployTx.Note = fmt.Sprintf("salt %s; contract address %s", hexutil.Encode(salt[:]), contractAddr.String())
+	if err != nil {
+		deployTx.Note = err.Error() + "; " + deployTx.Note
+	}
+	return contractAddr, nil
+}
+
+// DeployCreate2FromTempAccount creates contractByteCodeStr with salt through factory and returns its deployment record,
+// without name. The deterministic deployment proxy is deployed first when it is missing, on a local or private chain.
+func DeployCreate2FromTempAccount(ethClient *ethclient.Client, keyStore *keystore.KeyStore, tempAccount accounts.Account, factory common.Address, salt [32]byte, contractByteCodeStr string, gas deployGas, chainId *big.Int) (*utils.Deployment, error) {
+	initCode, err := hex.DecodeString(contractByteCodeStr)
+	if err != nil {
+		return nil, err
+	}
+	if factory == utils.Create2FactoryAddr {
+		if err := utils.EnsureCreate2Factory(ethClient, keyStore, tempAccount, chainId); err != nil {
+			return nil, err
+		}
+	} else if err := utils.RequireContract(ethClient, factory); err != nil {
+		return nil, fmt.Errorf("--%s: %s", constValue.Create2Factory, err.Error())
+	}
+	txHash, contractAddr, err := utils.DeployContractCreate2(ethClient, keyStore, tempAccount, factory, salt, initCode, gas.Limit, gas.Price, chainId)
+	if err != nil {
+		return nil, err
+	}
+	printNotice(utils.TxExplorerUrl("Deploy contract with CREATE2 txHash", txHash.String(), chainId))
+	if err := checkCreate2Holdings(ethClient, factory, contractAddr); err != nil {
+		return nil, err
+	}
+	deployment, err := utils.NewDeployment(ethClient, viper.GetString(constValue.NetworkType), chainId, tempAccount.Address, txHash, contractAddr, initCode)
+	if err != nil {
+		return nil, err
+	}
+	deployment.Create2Factory = &factory
+	deployment.Salt = salt[:]
+	return deployment, nil
+}
+
+func PredictAddressCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "predictAddress --salt {salt} --config-path {config path}",
+		Short: "Compute the address of a CREATE2 deployment from the salt and the init code, without touching the network",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath := viper.GetString(constValue.ConfigPath)
+			initCodeStr := strings.TrimPrefix(viper.GetString(constValue.InitCode), "0x")
+			if (len(configPath) == 0) == (len(initCodeStr) == 0) {
+				return fmt.Errorf("expect exactly one of --%s and --%s", constValue.ConfigPath, constValue.InitCode)
+			}
+			contractSalt := ""
+			if len(configPath) != 0 {
+				_, contract, err := readContractConfig()
+				if err != nil {
+					return err
+				}
+				initCodeStr, contractSalt = contract.ContractData, contract.Salt
+			}
+			salt, err := saltFlag(contractSalt)
+			if err != nil {
+				return err
+			}
+			if salt == nil {
+				return fmt.Errorf("missing salt, set --%s or salt in the config", constValue.Salt)
+			}
+			factory, err := create2FactoryFlag()
+			if err != nil {
+				return err
+			}
+			prediction, err := predictCreate2(factory, *salt, initCodeStr)
+			if err != nil {
+				return err
+			}
+			if viper.GetString(constValue.Output) == constValue.OutputJSON {
+				data, err := json.MarshalIndent(prediction, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+			fmt.Println(prediction.Address.String())
+			return nil
+		},
+	}
+	cmd.Flags().String(constValue.ConfigPath, "", "config file path, predict the address of its contract")
+	cmd.Flags().String(constValue.Contract, "", "name of the contract, when the config has several")
+	cmd.Flags().String(constValue.InitCode, "", "hex init code, the byte code with the constructor arguments, instead of --config-path")
+	cmd.Flags().String(constValue.Salt, "", "CREATE2 salt, a 0x prefixed hex of up to 32 bytes or a label which is hashed with keccak256")
+	cmd.Flags().String(constValue.Create2Factory, utils.Create2FactoryAddr.String(), "CREATE2 factory, default to the deterministic deployment proxy")
+	cmd.Flags().String(constValue.Output, constValue.OutputText, "output format, text or json")
+	return cmd
+}
@@ -0,0 +1,81 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/binance-chain/token-bind-tool/config"
+	"github.com/binance-chain/token-bind-tool/contracts/bep20"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+func TestRequireCreate2Owner(t *testing.T) {
+	// The standard BEP20 of script/contract.json comes without abi.
+	require.Error(t, requireCreate2Owner(&config.Config{ContractData: "6080"}))
+	require.Error(t, requireCreate2Owner(&config.Config{Name: "token", ContractABI: `[{"type":"constructor","inputs":[{"name":"supply","type":"uint256"}]}]`}))
+	require.Error(t, requireCreate2Owner(&config.Config{Name: "token", ContractABI: `[]`}))
+	require.NoError(t, requireCreate2Owner(&config.Config{Name: "token", ContractABI: `[{"type":"constructor","inputs":[{"name":"supply","type":"uint256"},{"name":"owner","type":"address"}]}]`}))
+}
+
+func TestCheckCreate2Holdings(t *testing.T) {
+	bep20ABI, err := abi.JSON(strings.NewReader(bep20.Bep20ABI))
+	require.NoError(t, err)
+	factory := utils.Create2FactoryAddr
+	contractAddr := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+
+	cases := []struct {
+		name           string
+		owner          *common.Address
+		factoryBalance *big.Int
+		fails          bool
+	}{
+		{"owner and supply handed over", &owner, big.NewInt(0), false},
+		{"factory owns the contract", &factory, big.NewInt(0), true},
+		{"factory holds the supply", &owner, big.NewInt(1000), true},
+		{"not a BEP20", nil, nil, false},
+	}
+	for _, c := range cases {
+		stub := newStubRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+			require.Equal(t, "eth_call", method)
+			var call struct {
+				To   common.Address `json:"to"`
+				Data hexutil.Bytes  `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(params[0], &call))
+			require.Equal(t, contractAddr, call.To)
+			abiMethod, err := bep20ABI.MethodById(call.Data)
+			require.NoError(t, err)
+			var data []byte
+			switch {
+			case abiMethod.Name == "getOwner" && c.owner != nil:
+				data, err = abiMethod.Outputs.Pack(*c.owner)
+			case abiMethod.Name == "balanceOf" && c.factoryBalance != nil:
+				args, err := abiMethod.Inputs.UnpackValues(call.Data[4:])
+				require.NoError(t, err)
+				require.Equal(t, factory, args[0])
+				data, err = abiMethod.Outputs.Pack(c.factoryBalance)
+				require.NoError(t, err)
+			default:
+				return nil, fmt.Errorf("execution reverted")
+			}
+			require.NoError(t, err)
+			return hexutil.Encode(data), nil
+		})
+		err := checkCreate2Holdings(stub.ethClient(t), factory, contractAddr)
+		stub.Close()
+		if c.fails {
+			require.Error(t, err, c.name)
+		} else {
+			require.NoError(t, err, c.name)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package command
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+
+	"github.com/binance-chain/token-bind-tool/config"
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+// recordDeployment names deployment and writes it to --deployments-dir. Where it is written goes to stderr, so that
+// stdout keeps the output scripts read.
+func recordDeployment(deployment *utils.Deployment, name string) error {
+	deployment.Name = name
+	path, err := utils.SaveDeployment(viper.GetString(constValue.DeploymentsDir), deployment)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Deployment record of %s: %s\n", name, path)
+	return nil
+}
+
+// recordContractDeployment writes the deployment record of contract with its constructor arguments, ABI and artifact
+// hash. The record is named by the contract name, or else by the contract name in the artifact or the source, or else
+// by the config file name.
+func recordContractDeployment(deployment *utils.Deployment, contract *config.Config) error {
+	name := contract.Name
+	switch {
+	case len(name) != 0:
+	case contract.BuildArtifact != nil:
+		name = contract.BuildArtifact.ContractName
+	case contract.Compilation != nil:
+		name = contract.Compilation.ContractName
+	default:
+		configPath := viper.GetString(constValue.ConfigPath)
+		name = strings.TrimSuffix(filepath.Base(configPath), filepath.Ext(configPath))
+	}
+	encodedArgs, err := hex.DecodeString(contract.EncodedConstructorArgs)
+	if err != nil {
+		return err
+	}
+	deployment.ConstructorArgs = contract.ConstructorArgs
+	deployment.EncodedConstructorArgs = encodedArgs
+	if len(contract.ContractABI) != 0 {
+		deployment.ABI = json.RawMessage(contract.ContractABI)
+	}
+	if contract.BuildArtifact != nil {
+		deployment.ArtifactHash = contract.BuildArtifact.Hash
+	}
+	return recordDeployment(deployment, name)
+}
+
+// printDeployments prints the deployed addresses, or the deployment records with --output json: a record for a
+// single deployment and an array for several.
+func printDeployments(deployments []*utils.Deployment) error {
+	if viper.GetString(constValue.Output) == constValue.OutputJSON {
+		var value interface{} = deployments
+		if len(deployments) == 1 {
+			value = deployments[0]
+		}
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	for _, deployment := range deployments {
+		if len(deployments) == 1 {
+			fmt.Println(deployment.Address.String())
+		} else {
+			fmt.Println(fmt.Sprintf("%s %s", deployment.Name, deployment.Address.String()))
+		}
+	}
+	return nil
+}
+
+// deployedAddr reads the address of a "@Name" reference from the record of the contract Name deployed on
+// --network-type.
+func deployedAddr(ref string) (common.Address, error) {
+	name := strings.TrimPrefix(ref, "@")
+	deployment, err := utils.LoadDeployment(viper.GetString(constValue.DeploymentsDir), viper.GetString(constValue.NetworkType), name)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return deployment.Address, nil
+}
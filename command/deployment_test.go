@@ -0,0 +1,36 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+func TestAddrFlagDeploymentRef(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deployments")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	defer viper.Reset()
+
+	tokenAddr := common.HexToAddress("0x4E656459ed25bF986Eea1196Bc1B00665401645d")
+	_, err = utils.SaveDeployment(dir, &utils.Deployment{Name: "MyToken", Network: constValue.TestNet, Address: tokenAddr})
+	require.NoError(t, err)
+
+	viper.Set(constValue.DeploymentsDir, dir)
+	viper.Set(constValue.NetworkType, constValue.TestNet)
+	viper.Set(constValue.BEP20ContractAddr, "@MyToken")
+	addr, err := addrFlag(constValue.BEP20ContractAddr)
+	require.NoError(t, err)
+	require.Equal(t, tokenAddr, addr)
+
+	viper.Set(constValue.NetworkType, constValue.Mainnet)
+	_, err = addrFlag(constValue.BEP20ContractAddr)
+	require.EqualError(t, err, "--bep20-contract-addr: no deployment of MyToken on mainnet in "+dir)
+}
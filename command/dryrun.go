@@ -0,0 +1,259 @@
+package command
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/viper"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/contracts/bep20"
+	"github.com/binance-chain/token-bind-tool/contracts/ownable"
+	"github.com/binance-chain/token-bind-tool/contracts/tokenhub"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+// PlannedTx is a transaction built by a dry run. It is never signed.
+type PlannedTx struct {
+	Step     string             `json:"step"`
+	From     common.Address     `json:"from"`
+	To       *common.Address    `json:"to"`
+	Value    *hexutil.Big       `json:"value"`
+	Nonce    hexutil.Uint64     `json:"nonce"`
+	Gas      hexutil.Uint64     `json:"gas"`
+	GasPrice *hexutil.Big       `json:"gas_price"`
+	Data     hexutil.Bytes      `json:"data"`
+	Call     *utils.DecodedCall `json:"call,omitempty"`
+	Note     string             `json:"note,omitempty"`
+}
+
+// DryRunPlan is every transaction a command would send, in order.
+type DryRunPlan struct {
+	Command      string       `json:"command"`
+	Network      string       `json:"network"`
+	ChainId      *hexutil.Big `json:"chain_id"`
+	From         string       `json:"from"`
+	LockAmount   *hexutil.Big `json:"lock_amount,omitempty"`
+	RelayFee     *hexutil.Big `json:"relay_fee,omitempty"`
+	Transactions []*PlannedTx `json:"transactions"`
+
+	nonce uint64
+}
+
+func newDryRunPlan(ethClient *ethclient.Client, command string, from common.Address, chainId *big.Int) (*DryRunPlan, error) {
+	nonce, err := ethClient.PendingNonceAt(context.Background(), from)
+	if err != nil {
+		return nil, err
+	}
+	return &DryRunPlan{
+		Command: command,
+		Network: viper.GetString(constValue.NetworkType),
+		ChainId: (*hexutil.Big)(chainId),
+		From:    from.String(),
+		nonce:   nonce,
+	}, nil
+}
+
+// add appends a transaction with the next nonce and decodes its calldata, or its constructor arguments for a
+// contract creation.
+func (plan *DryRunPlan) add(step string, to *common.Address, value *big.Int, data []byte, gas uint64) *PlannedTx {
+	tx := &PlannedTx{
+		Step:     step,
+		From:     common.HexToAddress(plan.From),
+		To:       to,
+		Value:    (*hexutil.Big)(value),
+		Nonce:    hexutil.Uint64(plan.nonce),
+		Gas:      hexutil.Uint64(gas),
+		GasPrice: (*hexutil.Big)(big.NewInt(constValue.DefaultGasPrice)),
+		Data:     data,
+	}
+	plan.nonce++
+	var err error
+	if to == nil {
+		tx.Call, err = utils.DecodeCreation(data)
+	} else if len(data) != 0 {
+		tx.Call, err = utils.DecodeCalldata(data)
+	}
+	if err != nil {
+		tx.Note = err.Error()
+	}
+	plan.Transactions = append(plan.Transactions, tx)
+	return tx
+}
+
+// planApproveBind adds approve and approveBind with the relay fee. With refundTo the refund of the rest BEP20 balance
+// is added, with newOwner the ownership transfer, as approveBindAndTransferOwnership sends them.
+func planApproveBind(plan *DryRunPlan, ethClient *ethclient.Client, bep20ContractAddr common.Address, bep2Symbol string, peggyAmount *big.Int, refundTo *common.Address, newOwner *common.Address) error {
+	bep20ABI, err := abi.JSON(strings.NewReader(bep20.Bep20ABI))
+	if err != nil {
+		return err
+	}
+	tokenManagerABI, err := abi.JSON(strings.NewReader(constValue.TokenManagerABI))
+	if err != nil {
+		return err
+	}
+	lockAmount, err := getLockAmount(ethClient, bep20ContractAddr, bep2Symbol, peggyAmount)
+	if err != nil {
+		return err
+	}
+	tokenhubInstance, err := tokenhub.NewTokenhub(constValue.TokenHubContractAddr, ethClient)
+	if err != nil {
+		return err
+	}
+	relayFee, err := tokenhubInstance.GetMiniRelayFee(utils.GetCallOpts())
+	if err != nil {
+		return err
+	}
+	plan.LockAmount = (*hexutil.Big)(lockAmount)
+	plan.RelayFee = (*hexutil.Big)(relayFee)
+
+	approveData, err := bep20ABI.Pack("approve", constValue.TokenManagerContractAddr, lockAmount)
+	if err != nil {
+		return err
+	}
+	plan.add("approve", &bep20ContractAddr, big.NewInt(0), approveData, constValue.DefaultGasLimit)
+	approveBindData, err := tokenManagerABI.Pack("approveBind", bep20ContractAddr, bep2Symbol)
+	if err != nil {
+		return err
+	}
+	plan.add("approveBind", &constValue.TokenManagerContractAddr, relayFee, approveBindData, constValue.DefaultGasLimit)
+
+	if refundTo != nil {
+		bep20Instance, err := bep20.NewBep20(bep20ContractAddr, ethClient)
+		if err != nil {
+			return err
+		}
+		balance, err := bep20Instance.BalanceOf(utils.GetCallOpts(), common.HexToAddress(plan.From))
+		if err != nil {
+			return err
+		}
+		if rest := new(big.Int).Sub(balance, lockAmount); rest.Sign() > 0 {
+			transferData, err := bep20ABI.Pack("transfer", *refundTo, rest)
+			if err != nil {
+				return err
+			}
+			plan.add("refund rest BEP20 balance", &bep20ContractAddr, big.NewInt(0), transferData, constValue.DefaultGasLimit)
+		}
+	}
+	if newOwner != nil {
+		return planTransferOwnership(plan, bep20ContractAddr, *newOwner)
+	}
+	return nil
+}
+
+// planDeploy adds the deployment of contractByteCodeStr and returns the address the contract will be created at.
+func planDeploy(plan *DryRunPlan, contractByteCodeStr string, gas deployGas) (common.Address, error) {
+	contractByteCode, err := hex.DecodeString(contractByteCodeStr)
+	if err != nil {
+		return common.Address{}, err
+	}
+	contractAddr := crypto.CreateAddress(common.HexToAddress(plan.From), plan.nonce)
+	deployTx := plan.add("deploy contract", nil, big.NewInt(0), contractByteCode, gas.Limit)
+	deployTx.GasPrice = (*hexutil.Big)(gas.Price)
+	deployTx.Note = strings.TrimPrefix(deployTx.Note+"; "+fmt.Sprintf("contract address %s", contractAddr.String()), "; ")
+	return contractAddr, nil
+}
+
+// planDeployAndTransfer adds the deployment of contractByteCodeStr, the transfer of the total supply and the ownership
+// transfer to newOwner. The total supply is only known once the constructor has run, so the transfer amount is left
+// as it is read after the deployment.
+func planDeployAndTransfer(plan *DryRunPlan, contractByteCodeStr string, gas deployGas, newOwner common.Address) error {
+	contractAddr, err := planDeploy(plan, contractByteCodeStr, gas)
+	if err != nil {
+		return err
+	}
+
+	bep20ABI, err := abi.JSON(strings.NewReader(bep20.Bep20ABI))
+	if err != nil {
+		return err
+	}
+	transferData, err := bep20ABI.Pack("transfer", newOwner, big.NewInt(0))
+	if err != nil {
+		return err
+	}
+	transferTx := plan.add("transfer total supply", &contractAddr, big.NewInt(0), transferData, constValue.DefaultGasLimit)
+	transferTx.Note = "the amount is the totalSupply() of the deployed contract, read after the deployment"
+	return planTransferOwnership(plan, contractAddr, newOwner)
+}
+
+func planTransferOwnership(plan *DryRunPlan, bep20ContractAddr common.Address, newOwner common.Address) error {
+	ownableABI, err := abi.JSON(strings.NewReader(ownable.OwnableABI))
+	if err != nil {
+		return err
+	}
+	data, err := ownableABI.Pack("transferOwnership", newOwner)
+	if err != nil {
+		return err
+	}
+	plan.add("transferOwnership", &bep20ContractAddr, big.NewInt(0), data, constValue.DefaultGasLimit)
+	return nil
+}
+
+// planRefundRestBNB adds the transfer of the whole BNB balance minus the transfer fee, as refundRestBNB sends it.
+func planRefundRestBNB(plan *DryRunPlan, ethClient *ethclient.Client, recipient common.Address) error {
+	balance, err := ethClient.BalanceAt(context.Background(), common.HexToAddress(plan.From), nil)
+	if err != nil {
+		return err
+	}
+	txFee := new(big.Int).Mul(big.NewInt(21000), big.NewInt(constValue.DefaultGasPrice))
+	if balance.Cmp(txFee) < 0 {
+		return fmt.Errorf("rest BNB %s is less than minimum transfer transaction fee %s", balance.String(), txFee.String())
+	}
+	plan.add("refund rest BNB", &recipient, new(big.Int).Sub(balance, txFee), nil, 21000)
+	return nil
+}
+
+// printDryRunPlan prints plan as text or, with --output json, as json.
+func printDryRunPlan(plan *DryRunPlan) error {
+	if viper.GetString(constValue.Output) == constValue.OutputJSON {
+		data, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Println(fmt.Sprintf("Dry run of %s on %s (chain id %s), nothing is signed or sent", plan.Command, plan.Network, plan.ChainId.ToInt().String()))
+	fmt.Println(fmt.Sprintf("From: %s", plan.From))
+	if plan.LockAmount != nil {
+		fmt.Println(fmt.Sprintf("Lock amount: %s", plan.LockAmount.ToInt().String()))
+	}
+	if plan.RelayFee != nil {
+		fmt.Println(fmt.Sprintf("Relay fee: %s", plan.RelayFee.ToInt().String()))
+	}
+	for idx, tx := range plan.Transactions {
+		to := "contract creation"
+		if tx.To != nil {
+			to = tx.To.String()
+		}
+		fmt.Println(fmt.Sprintf("%d. %s", idx+1, tx.Step))
+		fmt.Println(fmt.Sprintf("   to: %s, value: %s, nonce: %d, gas: %d, gas price: %s", to, tx.Value.ToInt().String(), uint64(tx.Nonce), uint64(tx.Gas), tx.GasPrice.ToInt().String()))
+		if len(tx.Data) != 0 {
+			fmt.Println(fmt.Sprintf("   data: %s", tx.Data.String()))
+		}
+		if tx.Call != nil {
+			fmt.Println(fmt.Sprintf("   call: %s", tx.Call.String()))
+		}
+		if len(tx.Note) != 0 {
+			fmt.Println(fmt.Sprintf("   note: %s", tx.Note))
+		}
+	}
+	return nil
+}
+
+// checkDryRunFlags rejects --dry-run together with --simulate, which are both ways of not sending.
+func checkDryRunFlags() error {
+	if viper.GetBool(constValue.DryRun) && viper.GetBool(constValue.Simulate) {
+		return fmt.Errorf("--%s and --%s can not be used together", constValue.DryRun, constValue.Simulate)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+func TestPlanDeployAndTransfer(t *testing.T) {
+	from := common.HexToAddress("0x4E656459ed25bF986Eea1196Bc1B00665401645d")
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	plan := &DryRunPlan{From: from.String(), nonce: 7}
+
+	require.NoError(t, planDeployAndTransfer(plan, "6080604052", defaultDeployGas(), owner))
+	require.Len(t, plan.Transactions, 3)
+
+	contractAddr := crypto.CreateAddress(from, 7)
+	require.Nil(t, plan.Transactions[0].To)
+	for idx, tx := range plan.Transactions {
+		require.Equal(t, uint64(7+idx), uint64(tx.Nonce))
+		if idx > 0 {
+			require.Equal(t, contractAddr, *tx.To)
+		}
+	}
+	require.Equal(t, "transfer", plan.Transactions[1].Call.Method)
+	require.Equal(t, "transferOwnership", plan.Transactions[2].Call.Method)
+	require.Equal(t, owner.String(), plan.Transactions[2].Call.Args[0].Value)
+	require.Equal(t, big.NewInt(0), plan.Transactions[2].Value.ToInt())
+}
+
+func TestPlanCreate2Deploy(t *testing.T) {
+	from := common.HexToAddress("0x4E656459ed25bF986Eea1196Bc1B00665401645d")
+	plan := &DryRunPlan{From: from.String(), nonce: 3}
+	salt, err := utils.ParseSalt("0x01")
+	require.NoError(t, err)
+
+	contractAddr, err := planCreate2Deploy(plan, utils.Create2FactoryAddr, salt, "6080604052", defaultDeployGas())
+	require.NoError(t, err)
+	prediction, err := predictCreate2(utils.Create2FactoryAddr, salt, "6080604052")
+	require.NoError(t, err)
+	require.Equal(t, prediction.Address, contractAddr)
+
+	require.Len(t, plan.Transactions, 1)
+	require.Equal(t, utils.Create2FactoryAddr, *plan.Transactions[0].To)
+	require.Equal(t, append(salt[:], 0x60, 0x80, 0x60, 0x40, 0x52), []byte(plan.Transactions[0].Data))
+}
@@ -0,0 +1,316 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/contracts/bep20"
+	tokenmanager "github.com/binance-chain/token-bind-tool/contracts/tokenmanger"
+	"github.com/binance-chain/token-bind-tool/types"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+const (
+	bindSuccessEvent = "bindSuccess"
+	bindFailureEvent = "bindFailure"
+
+	// indexMaxRetry is how many times a single block query is retried, once a failing range has shrunk to one block.
+	indexMaxRetry     = 5
+	indexMinChunkSize = 1
+)
+
+func IndexBindsCmd() *cobra.Command {
+	const (
+		flagFromBlock    = "from-block"
+		flagToBlock      = "to-block"
+		flagChunkSize    = "chunk-size"
+		flagOutputFormat = "output-format"
+		flagOutputPath   = "output-path"
+	)
+	cmd := &cobra.Command{
+		Use:   "indexBinds --from-block {from block} --to-block {to block}",
+		Short: "Scan all bindSuccess and bindFailure events of TokenManager and export them to csv, json or sqlite",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ethClient, _, err := getEnv()
+			if err != nil {
+				return err
+			}
+
+			outputPath := viper.GetString(flagOutputPath)
+			if len(outputPath) == 0 {
+				return fmt.Errorf("missing output path")
+			}
+			store, err := newBindRecordStore(viper.GetString(flagOutputFormat), outputPath)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			fromBlock, err := resumeFromBlock(outputPath, viper.GetUint64(flagFromBlock))
+			if err != nil {
+				return err
+			}
+
+			toBlock := viper.GetUint64(flagToBlock)
+			if toBlock == 0 {
+				header, err := ethClient.HeaderByNumber(context.Background(), nil)
+				if err != nil {
+					return err
+				}
+				toBlock = header.Number.Uint64()
+			}
+			if fromBlock > toBlock {
+				fmt.Println(fmt.Sprintf("Nothing to index, from block %d is larger than to block %d", fromBlock, toBlock))
+				return nil
+			}
+
+			chunkSize := viper.GetUint64(flagChunkSize)
+			if chunkSize == 0 {
+				return fmt.Errorf("chunk size must be positive")
+			}
+			indexer, err := newBindIndexer(ethClient)
+			if err != nil {
+				return err
+			}
+			return indexer.Run(fromBlock, toBlock, chunkSize, store, outputPath)
+		},
+	}
+	cmd.Flags().Uint64(flagFromBlock, 0, "first block to scan")
+	cmd.Flags().Uint64(flagToBlock, 0, "last block to scan, 0 means the latest block")
+	cmd.Flags().Uint64(flagChunkSize, 5000, "initial block range of a single log query, it shrinks automatically when the RPC rejects the range")
+	cmd.Flags().String(flagOutputFormat, "csv", "output format, csv, json or sqlite")
+	cmd.Flags().String(flagOutputPath, "binds.csv", "output file path")
+	return cmd
+}
+
+type bindIndexer struct {
+	ethClient    *ethclient.Client
+	filterer     *tokenmanager.TokenmanagerFilterer
+	tokenCache   map[common.Address]*types.BindRecord
+	blockTimeMap map[uint64]uint64
+
+	// fetch queries the events of a block range, retryDelay is the base delay before a failed query is retried.
+	fetch      func(start, end uint64) ([]*types.BindRecord, error)
+	retryDelay time.Duration
+}
+
+func newBindIndexer(ethClient *ethclient.Client) (*bindIndexer, error) {
+	filterer, err := tokenmanager.NewTokenmanagerFilterer(constValue.TokenManagerContractAddr, ethClient)
+	if err != nil {
+		return nil, err
+	}
+	indexer := &bindIndexer{
+		ethClient:    ethClient,
+		filterer:     filterer,
+		tokenCache:   make(map[common.Address]*types.BindRecord),
+		blockTimeMap: make(map[uint64]uint64),
+		retryDelay:   time.Second,
+	}
+	indexer.fetch = indexer.fetchLogs
+	return indexer, nil
+}
+
+// Run pages through [fromBlock, toBlock]. A failed query is retried and its range halved until the RPC accepts it, down
+// to a single block which is retried indexMaxRetry times. A successful query lets the range grow back towards
+// chunkSize. The cursor is saved after every page.
+func (indexer *bindIndexer) Run(fromBlock, toBlock, chunkSize uint64, store bindRecordStore, outputPath string) error {
+	currentSize := chunkSize
+	total := 0
+	for start := fromBlock; start <= toBlock; {
+		end := start + currentSize - 1
+		if end > toBlock {
+			end = toBlock
+		}
+		var records []*types.BindRecord
+		var err error
+		for retry := 0; ; {
+			records, err = indexer.fetch(start, end)
+			if err == nil {
+				break
+			}
+			if currentSize > indexMinChunkSize {
+				currentSize = currentSize / 2
+				if start+currentSize-1 < end {
+					end = start + currentSize - 1
+				}
+			} else if retry++; retry > indexMaxRetry {
+				return fmt.Errorf("failed to query logs in block range [%d, %d]: %s", start, end, err.Error())
+			}
+			fmt.Println(fmt.Sprintf("Query logs failed: %s, retry with block range [%d, %d]", err.Error(), start, end))
+			time.Sleep(time.Duration(retry+1) * indexer.retryDelay)
+		}
+
+		if err := store.Write(records); err != nil {
+			return err
+		}
+		if err := writeIndexCursor(outputPath, end); err != nil {
+			return err
+		}
+		total += len(records)
+		fmt.Println(fmt.Sprintf("Indexed block range [%d, %d], found %d events", start, end, len(records)))
+
+		start = end + 1
+		if currentSize < chunkSize {
+			currentSize = currentSize * 2
+			if currentSize > chunkSize {
+				currentSize = chunkSize
+			}
+		}
+	}
+	fmt.Println(fmt.Sprintf("Finished indexing up to block %d, %d events in total", toBlock, total))
+	return nil
+}
+
+func (indexer *bindIndexer) fetchLogs(start, end uint64) ([]*types.BindRecord, error) {
+	opts := &bind.FilterOpts{
+		Start:   start,
+		End:     &end,
+		Context: context.Background(),
+	}
+	var records []*types.BindRecord
+
+	successIter, err := indexer.filterer.FilterBindSuccess(opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	for successIter.Next() {
+		event := successIter.Event
+		record := &types.BindRecord{
+			BlockNumber:  event.Raw.BlockNumber,
+			TxHash:       event.Raw.TxHash.String(),
+			LogIndex:     event.Raw.Index,
+			Event:        bindSuccessEvent,
+			ContractAddr: event.ContractAddr.String(),
+			Bep2Symbol:   event.Bep2Symbol,
+			TotalSupply:  event.TotalSupply.String(),
+			PeggyAmount:  event.PeggyAmount.String(),
+		}
+		records = append(records, record)
+	}
+	if err := successIter.Error(); err != nil {
+		return nil, err
+	}
+
+	failureIter, err := indexer.filterer.FilterBindFailure(opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	for failureIter.Next() {
+		event := failureIter.Event
+		record := &types.BindRecord{
+			BlockNumber:  event.Raw.BlockNumber,
+			TxHash:       event.Raw.TxHash.String(),
+			LogIndex:     event.Raw.Index,
+			Event:        bindFailureEvent,
+			ContractAddr: event.ContractAddr.String(),
+			Bep2Symbol:   event.Bep2Symbol,
+			FailedReason: event.FailedReason,
+		}
+		records = append(records, record)
+	}
+	if err := failureIter.Error(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].BlockNumber != records[j].BlockNumber {
+			return records[i].BlockNumber < records[j].BlockNumber
+		}
+		return records[i].LogIndex < records[j].LogIndex
+	})
+	for _, record := range records {
+		if err := indexer.enrich(record); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+func (indexer *bindIndexer) enrich(record *types.BindRecord) error {
+	blockTime, ok := indexer.blockTimeMap[record.BlockNumber]
+	if !ok {
+		header, err := indexer.ethClient.HeaderByNumber(context.Background(), new(big.Int).SetUint64(record.BlockNumber))
+		if err != nil {
+			return err
+		}
+		blockTime = header.Time
+		indexer.blockTimeMap[record.BlockNumber] = blockTime
+	}
+	record.BlockTime = blockTime
+
+	contractAddr := common.HexToAddress(record.ContractAddr)
+	token, ok := indexer.tokenCache[contractAddr]
+	if !ok {
+		token = &types.BindRecord{}
+		bep20Instance, err := bep20.NewBep20(contractAddr, indexer.ethClient)
+		if err != nil {
+			return err
+		}
+		// Tokens which do not follow BEP20 still show up in bindFailure, so metadata is best effort.
+		if name, err := bep20Instance.Name(utils.GetCallOpts()); err == nil {
+			token.Name = name
+		}
+		if symbol, err := bep20Instance.Symbol(utils.GetCallOpts()); err == nil {
+			token.Symbol = symbol
+		}
+		if decimals, err := bep20Instance.Decimals(utils.GetCallOpts()); err == nil {
+			token.Decimals = decimals.Int64()
+		}
+		indexer.tokenCache[contractAddr] = token
+	}
+	record.Name = token.Name
+	record.Symbol = token.Symbol
+	record.Decimals = token.Decimals
+	return nil
+}
+
+// resumeFromBlock returns the block after the last indexed block of outputPath when it is beyond fromBlock, otherwise
+// fromBlock.
+func resumeFromBlock(outputPath string, fromBlock uint64) (uint64, error) {
+	lastIndexed, found, err := readIndexCursor(outputPath)
+	if err != nil {
+		return 0, err
+	}
+	if found && lastIndexed+1 > fromBlock {
+		fmt.Println(fmt.Sprintf("Continue from block %d, the last indexed block is %d", lastIndexed+1, lastIndexed))
+		return lastIndexed + 1, nil
+	}
+	return fromBlock, nil
+}
+
+func indexCursorPath(outputPath string) string {
+	return outputPath + ".cursor"
+}
+
+func readIndexCursor(outputPath string) (uint64, bool, error) {
+	data, err := ioutil.ReadFile(indexCursorPath(outputPath))
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	lastIndexed, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid index cursor file %s: %s", indexCursorPath(outputPath), err.Error())
+	}
+	return lastIndexed, true, nil
+}
+
+func writeIndexCursor(outputPath string, lastIndexed uint64) error {
+	return ioutil.WriteFile(indexCursorPath(outputPath), []byte(strconv.FormatUint(lastIndexed, 10)), 0644)
+}
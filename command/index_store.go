@@ -0,0 +1,262 @@
+package command
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/binance-chain/token-bind-tool/types"
+)
+
+const (
+	outputFormatCSV    = "csv"
+	outputFormatJSON   = "json"
+	outputFormatSQLite = "sqlite"
+)
+
+var bindRecordCSVHeader = []string{
+	"block_number", "block_time", "tx_hash", "log_index", "event", "contract_addr", "bep2_symbol",
+	"total_supply", "peggy_amount", "failed_reason", "name", "symbol", "decimals",
+}
+
+// bindRecordStore persists indexed bind events. Write is called once per block range, in block order. A range may be
+// written again after a crash before the cursor is saved, so stores skip the events they already hold.
+type bindRecordStore interface {
+	Write(records []*types.BindRecord) error
+	Close() error
+}
+
+// bindRecordKey identifies an event by its transaction and log index.
+func bindRecordKey(txHash string, logIndex uint) string {
+	return fmt.Sprintf("%s:%d", strings.ToLower(txHash), logIndex)
+}
+
+func newBindRecordStore(format, outputPath string) (bindRecordStore, error) {
+	switch format {
+	case outputFormatCSV:
+		return newCSVBindRecordStore(outputPath)
+	case outputFormatJSON:
+		return newJSONBindRecordStore(outputPath)
+	case outputFormatSQLite:
+		return newSQLiteBindRecordStore(outputPath)
+	default:
+		return nil, fmt.Errorf("unsupported output format %s, expect %s, %s or %s", format, outputFormatCSV, outputFormatJSON, outputFormatSQLite)
+	}
+}
+
+type csvBindRecordStore struct {
+	file   *os.File
+	writer *csv.Writer
+	seen   map[string]bool
+}
+
+func newCSVBindRecordStore(outputPath string) (*csvBindRecordStore, error) {
+	seen, err := readCSVBindRecordKeys(outputPath)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(outputPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	writer := csv.NewWriter(file)
+	if seen == nil {
+		if err := writer.Write(bindRecordCSVHeader); err != nil {
+			file.Close()
+			return nil, err
+		}
+		writer.Flush()
+		seen = make(map[string]bool)
+	}
+	return &csvBindRecordStore{file: file, writer: writer, seen: seen}, nil
+}
+
+// readCSVBindRecordKeys returns the keys of the events in the csv at outputPath, or nil when there is no csv yet.
+func readCSVBindRecordKeys(outputPath string) (map[string]bool, error) {
+	file, err := os.Open(outputPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse existing output %s: %s", outputPath, err.Error())
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	seen := make(map[string]bool, len(rows)-1)
+	for _, row := range rows[1:] {
+		if len(row) != len(bindRecordCSVHeader) {
+			return nil, fmt.Errorf("failed to parse existing output %s: expect %d columns", outputPath, len(bindRecordCSVHeader))
+		}
+		logIndex, err := strconv.ParseUint(row[3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse existing output %s: invalid log index %s", outputPath, row[3])
+		}
+		seen[bindRecordKey(row[2], uint(logIndex))] = true
+	}
+	return seen, nil
+}
+
+func (store *csvBindRecordStore) Write(records []*types.BindRecord) error {
+	for _, record := range records {
+		key := bindRecordKey(record.TxHash, record.LogIndex)
+		if store.seen[key] {
+			continue
+		}
+		store.seen[key] = true
+		row := []string{
+			strconv.FormatUint(record.BlockNumber, 10),
+			strconv.FormatUint(record.BlockTime, 10),
+			record.TxHash,
+			strconv.FormatUint(uint64(record.LogIndex), 10),
+			record.Event,
+			record.ContractAddr,
+			record.Bep2Symbol,
+			record.TotalSupply,
+			record.PeggyAmount,
+			strconv.FormatUint(uint64(record.FailedReason), 10),
+			record.Name,
+			record.Symbol,
+			strconv.FormatInt(record.Decimals, 10),
+		}
+		if err := store.writer.Write(row); err != nil {
+			return err
+		}
+	}
+	store.writer.Flush()
+	return store.writer.Error()
+}
+
+func (store *csvBindRecordStore) Close() error {
+	return store.file.Close()
+}
+
+// jsonBindRecordStore keeps the whole array in memory and rewrites the file after every range,
+// so the file is always a valid json document.
+type jsonBindRecordStore struct {
+	outputPath string
+	records    []*types.BindRecord
+	seen       map[string]bool
+}
+
+func newJSONBindRecordStore(outputPath string) (*jsonBindRecordStore, error) {
+	store := &jsonBindRecordStore{outputPath: outputPath, seen: make(map[string]bool)}
+	data, err := ioutil.ReadFile(outputPath)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &store.records); err != nil {
+			return nil, fmt.Errorf("failed to parse existing output %s: %s", outputPath, err.Error())
+		}
+	}
+	for _, record := range store.records {
+		store.seen[bindRecordKey(record.TxHash, record.LogIndex)] = true
+	}
+	return store, nil
+}
+
+func (store *jsonBindRecordStore) Write(records []*types.BindRecord) error {
+	if len(records) == 0 {
+		if _, err := os.Stat(store.outputPath); err == nil {
+			return nil
+		}
+	}
+	for _, record := range records {
+		key := bindRecordKey(record.TxHash, record.LogIndex)
+		if !store.seen[key] {
+			store.seen[key] = true
+			store.records = append(store.records, record)
+		}
+	}
+	if store.records == nil {
+		store.records = []*types.BindRecord{}
+	}
+	data, err := json.MarshalIndent(store.records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(store.outputPath, data, 0644)
+}
+
+func (store *jsonBindRecordStore) Close() error {
+	return nil
+}
+
+// sqliteBindRecordStore needs cgo, go-sqlite3 fails to open a database in a binary built with CGO_ENABLED=0.
+type sqliteBindRecordStore struct {
+	db *sql.DB
+}
+
+func newSQLiteBindRecordStore(outputPath string) (*sqliteBindRecordStore, error) {
+	db, err := sql.Open("sqlite3", outputPath)
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS binds (
+		block_number INTEGER NOT NULL,
+		block_time INTEGER NOT NULL,
+		tx_hash TEXT NOT NULL,
+		log_index INTEGER NOT NULL,
+		event TEXT NOT NULL,
+		contract_addr TEXT NOT NULL,
+		bep2_symbol TEXT NOT NULL,
+		total_supply TEXT,
+		peggy_amount TEXT,
+		failed_reason INTEGER,
+		name TEXT,
+		symbol TEXT,
+		decimals INTEGER,
+		PRIMARY KEY (tx_hash, log_index)
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqliteBindRecordStore{db: db}, nil
+}
+
+func (store *sqliteBindRecordStore) Write(records []*types.BindRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	tx, err := store.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`INSERT OR IGNORE INTO binds VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, record := range records {
+		_, err := stmt.Exec(record.BlockNumber, record.BlockTime, record.TxHash, record.LogIndex, record.Event,
+			record.ContractAddr, record.Bep2Symbol, record.TotalSupply, record.PeggyAmount, record.FailedReason,
+			record.Name, record.Symbol, record.Decimals)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (store *sqliteBindRecordStore) Close() error {
+	return store.db.Close()
+}
@@ -0,0 +1,186 @@
+package command
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/binance-chain/token-bind-tool/types"
+)
+
+func testBindRecords() []*types.BindRecord {
+	return []*types.BindRecord{
+		{BlockNumber: 10, BlockTime: 1000, TxHash: "0xaa", LogIndex: 1, Event: bindSuccessEvent, ContractAddr: "0x01", Bep2Symbol: "ABC-123", TotalSupply: "100", PeggyAmount: "0", Name: "ABC", Symbol: "ABC", Decimals: 18},
+		{BlockNumber: 12, BlockTime: 1006, TxHash: "0xbb", LogIndex: 0, Event: bindFailureEvent, ContractAddr: "0x02", Bep2Symbol: "XYZ-456", FailedReason: 2, Symbol: "XYZ", Decimals: 8},
+	}
+}
+
+func TestBindRecordStores(t *testing.T) {
+	dir, err := ioutil.TempDir("", "index")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	records := testBindRecords()
+
+	for _, format := range []string{outputFormatCSV, outputFormatJSON, outputFormatSQLite} {
+		outputPath := filepath.Join(dir, "binds."+format)
+		store, err := newBindRecordStore(format, outputPath)
+		require.NoError(t, err)
+		require.NoError(t, store.Write(records[:1]))
+		require.NoError(t, store.Close())
+
+		// A range written again after a crash, before the cursor was saved, must not duplicate events.
+		store, err = newBindRecordStore(format, outputPath)
+		require.NoError(t, err)
+		require.NoError(t, store.Write(records))
+		require.NoError(t, store.Write(nil))
+		require.NoError(t, store.Close())
+
+		keys, err := readStoredBindRecordKeys(format, outputPath)
+		require.NoError(t, err, format)
+		require.Equal(t, []string{bindRecordKey("0xaa", 1), bindRecordKey("0xbb", 0)}, keys, format)
+	}
+
+	jsonStore, err := newJSONBindRecordStore(filepath.Join(dir, "binds.json"))
+	require.NoError(t, err)
+	require.Equal(t, records, jsonStore.records)
+
+	_, err = newBindRecordStore("xml", filepath.Join(dir, "binds.xml"))
+	require.Error(t, err)
+}
+
+func readStoredBindRecordKeys(format, outputPath string) ([]string, error) {
+	var keys []string
+	switch format {
+	case outputFormatCSV:
+		file, err := os.Open(outputPath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		rows, err := csv.NewReader(file).ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(rows[0], bindRecordCSVHeader) {
+			return nil, fmt.Errorf("unexpected csv header %v", rows[0])
+		}
+		for _, row := range rows[1:] {
+			keys = append(keys, row[2]+":"+row[3])
+		}
+	case outputFormatJSON:
+		store, err := newJSONBindRecordStore(outputPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range store.records {
+			keys = append(keys, bindRecordKey(record.TxHash, record.LogIndex))
+		}
+	case outputFormatSQLite:
+		db, err := sql.Open("sqlite3", outputPath)
+		if err != nil {
+			return nil, err
+		}
+		defer db.Close()
+		rows, err := db.Query("SELECT tx_hash, log_index FROM binds ORDER BY block_number")
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var txHash string
+			var logIndex uint
+			if err := rows.Scan(&txHash, &logIndex); err != nil {
+				return nil, err
+			}
+			keys = append(keys, bindRecordKey(txHash, logIndex))
+		}
+	}
+	return keys, nil
+}
+
+func TestIndexerResumeFromCursor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "index")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	outputPath := filepath.Join(dir, "binds.json")
+
+	fromBlock, err := resumeFromBlock(outputPath, 5)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), fromBlock)
+
+	var ranges [][2]uint64
+	indexer := &bindIndexer{fetch: func(start, end uint64) ([]*types.BindRecord, error) {
+		ranges = append(ranges, [2]uint64{start, end})
+		var records []*types.BindRecord
+		for _, record := range testBindRecords() {
+			if record.BlockNumber >= start && record.BlockNumber <= end {
+				records = append(records, record)
+			}
+		}
+		return records, nil
+	}}
+	store, err := newJSONBindRecordStore(outputPath)
+	require.NoError(t, err)
+	require.NoError(t, indexer.Run(5, 11, 4, store, outputPath))
+	require.Equal(t, [][2]uint64{{5, 8}, {9, 11}}, ranges)
+
+	fromBlock, err = resumeFromBlock(outputPath, 5)
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), fromBlock)
+	fromBlock, err = resumeFromBlock(outputPath, 20)
+	require.NoError(t, err)
+	require.Equal(t, uint64(20), fromBlock)
+
+	ranges = nil
+	store, err = newJSONBindRecordStore(outputPath)
+	require.NoError(t, err)
+	require.NoError(t, indexer.Run(12, 13, 4, store, outputPath))
+	require.Equal(t, [][2]uint64{{12, 13}}, ranges)
+	require.Len(t, store.records, 2)
+
+	require.NoError(t, ioutil.WriteFile(indexCursorPath(outputPath), []byte("abc"), 0644))
+	_, err = resumeFromBlock(outputPath, 5)
+	require.Error(t, err)
+}
+
+func TestIndexerShrinksToSingleBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "index")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	outputPath := filepath.Join(dir, "binds.json")
+
+	// The RPC only accepts single block queries.
+	var sizes []uint64
+	indexer := &bindIndexer{fetch: func(start, end uint64) ([]*types.BindRecord, error) {
+		sizes = append(sizes, end-start+1)
+		if end != start {
+			return nil, fmt.Errorf("range too large")
+		}
+		return nil, nil
+	}}
+	store, err := newJSONBindRecordStore(outputPath)
+	require.NoError(t, err)
+	require.NoError(t, indexer.Run(100, 100, 5000, store, outputPath))
+	require.Equal(t, []uint64{1}, sizes)
+
+	sizes = nil
+	require.NoError(t, indexer.Run(101, 5100, 5000, store, outputPath+"2"))
+	require.Equal(t, []uint64{5000, 2500, 1250, 625, 312, 156, 78, 39, 19, 9, 4, 2, 1}, sizes[:13])
+
+	// A block which keeps failing is retried indexMaxRetry times.
+	calls := 0
+	indexer.fetch = func(start, end uint64) ([]*types.BindRecord, error) {
+		calls++
+		return nil, fmt.Errorf("unavailable")
+	}
+	err = indexer.Run(1, 1, 1, store, outputPath)
+	require.Error(t, err)
+	require.Equal(t, indexMaxRetry+1, calls)
+}
@@ -0,0 +1,644 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/contracts/bep20"
+	"github.com/binance-chain/token-bind-tool/contracts/tokenhub"
+	tokenmanager "github.com/binance-chain/token-bind-tool/contracts/tokenmanger"
+	"github.com/binance-chain/token-bind-tool/types"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+const (
+	suggestionSwapOffchain = "please swap tokens offchain, e.g., through CEXs (if your tokens are listed on Binance.com, " +
+		"you can ask your users deposit their tokens to it then do the exchange)"
+)
+
+// PreCheckReport is the machine-readable result of preCheck.
+type PreCheckReport struct {
+	Bep2Symbol        string           `json:"bep2_symbol"`
+	Bep20ContractAddr string           `json:"bep20_contract_addr"`
+	LimitsSource      string           `json:"limits_source"`
+	CanBind           bool             `json:"can_bind"`
+	Findings          []*types.Finding `json:"findings"`
+}
+
+// preCheckContext holds the token data shared by all checks, so that it is read from BC and BSC only once.
+type preCheckContext struct {
+	ethClient         *ethclient.Client
+	bep2Symbol        string
+	bep2Token         *types.Bep2
+	bep20ContractAddr common.Address
+	bep20Instance     *bep20.Bep20
+	bep20Symbol       string
+	bep20TotalSupply  *big.Int
+	bep20Decimals     *big.Int
+	bep20Owner        *common.Address
+	// bep20PeggyAmount is --peggy-amount in BEP20 units, nil when it is not given.
+	bep20PeggyAmount *big.Int
+	// lockAmount is the amount of BEP20 tokens approveBind locks in TokenHub, nil when it cannot be derived.
+	lockAmount *big.Int
+	limits     *bindLimits
+}
+
+type preCheckFunc func(ctx *preCheckContext) (*types.Finding, error)
+
+var preChecks = []preCheckFunc{
+	checkBep2NotBound,
+	checkSymbolLength,
+	checkSymbolMatch,
+	checkMaxSupply,
+	checkTotalSupplyMatch,
+	checkGetOwner,
+	checkBytecode,
+	checkBindPackageRecord,
+	checkApproveAmount,
+	checkOwnerBalance,
+	checkOwnerAllowance,
+	checkTokenHubBalance,
+	checkOwnerBNB,
+}
+
+func PreCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preCheck",
+		Short: "Verify whether the BEP2 and BEP20 can be bind-ed or not, and give suggestions based on different cases",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output := viper.GetString(constValue.Output)
+			if output != constValue.OutputText && output != constValue.OutputJSON &&
+				output != constValue.OutputTable && output != constValue.OutputMarkdown {
+				return fmt.Errorf("unsupported output %s, expect %s, %s, %s or %s", output,
+					constValue.OutputText, constValue.OutputJSON, constValue.OutputTable, constValue.OutputMarkdown)
+			}
+			ethClient, _, err := getEnv()
+			if err != nil {
+				return err
+			}
+
+			if err := bindConfigDefaults(false); err != nil {
+				return err
+			}
+			bep20ContractAddr, err := contractAddrFlag(ethClient, constValue.BEP20ContractAddr)
+			if err != nil {
+				return err
+			}
+			bep2Symbol := viper.GetString(constValue.BEP2Symbol)
+			if len(bep2Symbol) == 0 {
+				return fmt.Errorf("missing bep2 symbol")
+			}
+
+			peggyAmount, err := peggyAmountFlag(bep2Symbol)
+			if err != nil {
+				return err
+			}
+
+			tokenClient, err := getBcTokenClient()
+			if err != nil {
+				return err
+			}
+
+			report, err := PreCheckBind(ethClient, tokenClient, bep2Symbol, bep20ContractAddr, peggyAmount)
+			if err != nil {
+				return err
+			}
+			if err := renderPreCheckReport(report, output); err != nil {
+				return err
+			}
+			if !report.CanBind {
+				return fmt.Errorf("the BEP2 %s and BEP20 %s cannot bind", bep2Symbol, bep20ContractAddr)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String(constValue.BEP20ContractAddr, "", "bep20 contract address")
+	cmd.Flags().String(constValue.BEP2Symbol, "", "bep2 token symbol, default to the bep2_symbol in the config")
+	cmd.Flags().String(constValue.PeggyAmount, "", "peggy amount in the bind transaction, in whole BEP2 tokens like \"1.5M\" or \"1000 ABC\", or in BEP2 units with 8 decimals like \"100000000 wei\", default to the peggy_amount in the config. Without it the lock amount is read from the pending bind package")
+	cmd.Flags().String(constValue.ConfigPath, "", "config file path, take the bep2 symbol and the peggy amount from its contract")
+	cmd.Flags().String(constValue.Contract, "", "name of the contract in the config, when the config has several")
+	cmd.Flags().String(constValue.BcApiUrl, "", "Beacon Chain api url, like https://dex.binance.org/api/v1, default to the api of the selected network")
+	cmd.Flags().String(constValue.BcTokenFile, "", "local json file with the BEP2 token list, used instead of the Beacon Chain api")
+	cmd.Flags().String(constValue.BcTokenCachePath, constValue.BcTokenCache, "cache file of BEP2 tokens queried from the Beacon Chain api")
+	cmd.Flags().Duration(constValue.BcTokenCacheTTL, 0, "how long a cached BEP2 token stays valid, 0 disables the cache. A cached token misses binds and mints since it was fetched")
+	cmd.Flags().String(constValue.Output, constValue.OutputText, "output format, text, json, table or markdown")
+	return cmd
+}
+
+// PreCheckBind runs all preChecks. An error is only returned when the tokens cannot be read at all,
+// a failed check is reported as a Finding. peggyAmount may be nil.
+func PreCheckBind(ethClient *ethclient.Client, tokenClient *utils.BcTokenClient, bep2Symbol string, bep20ContractAddr common.Address, peggyAmount *big.Int) (*PreCheckReport, error) {
+	bep2Token, err := tokenClient.GetBep2Token(bep2Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	bep20Instance, err := bep20.NewBep20(bep20ContractAddr, ethClient)
+	if err != nil {
+		return nil, err
+	}
+	bep20Symbol, err := bep20Instance.Symbol(utils.GetCallOpts())
+	if err != nil {
+		return nil, err
+	}
+	bep20TotalSupply, err := bep20Instance.TotalSupply(utils.GetCallOpts())
+	if err != nil {
+		return nil, err
+	}
+	bep20Decimals, err := bep20Instance.Decimals(utils.GetCallOpts())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := &preCheckContext{
+		ethClient:         ethClient,
+		bep2Symbol:        bep2Symbol,
+		bep2Token:         bep2Token,
+		bep20ContractAddr: bep20ContractAddr,
+		bep20Instance:     bep20Instance,
+		bep20Symbol:       bep20Symbol,
+		bep20TotalSupply:  bep20TotalSupply,
+		bep20Decimals:     bep20Decimals,
+		limits:            getBindLimits(ethClient),
+	}
+	if owner, err := bep20Instance.GetOwner(utils.GetCallOpts()); err == nil {
+		ctx.bep20Owner = &owner
+	}
+	if peggyAmount != nil {
+		bep20PeggyAmount, err := utils.ConvertToBEP20Amount(peggyAmount, bep20Decimals.Int64(), utils.RoundExact)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peggy amount: %s", err.Error())
+		}
+		ctx.bep20PeggyAmount = bep20PeggyAmount
+		if lockAmount := new(big.Int).Sub(bep20TotalSupply, bep20PeggyAmount); lockAmount.Sign() >= 0 {
+			ctx.lockAmount = lockAmount
+		}
+	} else if tokenManagerInstance, err := tokenmanager.NewTokenmanager(constValue.TokenManagerContractAddr, ethClient); err == nil {
+		if lockAmount, err := tokenManagerInstance.QueryRequiredLockAmountForBind(utils.GetCallOpts(), bep2Symbol); err == nil {
+			ctx.lockAmount = lockAmount
+		}
+	}
+	report := &PreCheckReport{
+		Bep2Symbol:        bep2Symbol,
+		Bep20ContractAddr: bep20ContractAddr.String(),
+		LimitsSource:      ctx.limits.Source,
+		CanBind:           true,
+	}
+	for _, check := range preChecks {
+		finding, err := check(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if finding.Blocking() {
+			report.CanBind = false
+		}
+		report.Findings = append(report.Findings, finding)
+	}
+	return report, nil
+}
+
+func checkBep2NotBound(ctx *preCheckContext) (*types.Finding, error) {
+	finding := &types.Finding{
+		ID:       "BEP2_NOT_BOUND",
+		Title:    fmt.Sprintf("Checking BEP2 is not bound yet: %s", ctx.bep2Symbol),
+		Severity: types.SeverityBlocking,
+		Passed:   ctx.bep2Token.ContractAddress == nil || len(*ctx.bep2Token.ContractAddress) == 0,
+		Expected: "not bound",
+	}
+	if !finding.Passed {
+		finding.Message = fmt.Sprintf("the BEP2 %s is already bind to %s", ctx.bep2Symbol, *ctx.bep2Token.ContractAddress)
+		finding.Observed = *ctx.bep2Token.ContractAddress
+		finding.Suggestion = "a BEP2 token can only be bound once, no further action is needed if this is your BEP20 contract"
+	}
+	return finding, nil
+}
+
+func checkSymbolLength(ctx *preCheckContext) (*types.Finding, error) {
+	finding := &types.Finding{
+		ID:       "BEP20_SYMBOL_LENGTH",
+		Title:    fmt.Sprintf("Checking BEP20 symbol length: %s", ctx.bep20Symbol),
+		Severity: types.SeverityBlocking,
+		Passed:   len(ctx.bep20Symbol) >= ctx.limits.MinSymbolLen && len(ctx.bep20Symbol) <= ctx.limits.MaxSymbolLen,
+		Observed: fmt.Sprintf("%d", len(ctx.bep20Symbol)),
+		Expected: fmt.Sprintf("%d-%d", ctx.limits.MinSymbolLen, ctx.limits.MaxSymbolLen),
+	}
+	if !finding.Passed {
+		finding.Message = fmt.Sprintf("BEP20 symbol length should be between %d and %d", ctx.limits.MinSymbolLen, ctx.limits.MaxSymbolLen)
+		finding.Suggestion = suggestionSwapOffchain
+	}
+	return finding, nil
+}
+
+func checkSymbolMatch(ctx *preCheckContext) (*types.Finding, error) {
+	finding := &types.Finding{
+		ID:       "SYMBOL_MATCH",
+		Title:    fmt.Sprintf("Checking symbols match or not, BEP2: %s, BEP20: %s", ctx.bep2Symbol, ctx.bep20Symbol),
+		Severity: types.SeverityBlocking,
+		Passed:   strings.HasPrefix(ctx.bep2Symbol, ctx.bep20Symbol+"-"),
+		Observed: ctx.bep20Symbol,
+		Expected: strings.Split(ctx.bep2Symbol, "-")[0],
+	}
+	if !finding.Passed {
+		finding.Message = "BEP2 and BEP20 sybmols do not match"
+		finding.Suggestion = suggestionSwapOffchain
+	}
+	return finding, nil
+}
+
+func checkMaxSupply(ctx *preCheckContext) (*types.Finding, error) {
+	// Rounding the limit down is exact here: an integer supply is within the limit iff it is within its floor.
+	maxBep2Supply, err := utils.ConvertToBEP20Amount(ctx.limits.MaxBep2TotalSupply, ctx.bep20Decimals.Int64(), utils.RoundDown)
+	if err != nil {
+		return nil, err
+	}
+	finding := &types.Finding{
+		ID:       "BEP20_MAX_SUPPLY",
+		Title:    fmt.Sprintf("Checking BEP20 total supply exceeds the max BEP2 allowance or not: %s", ctx.bep20Symbol),
+		Severity: types.SeverityBlocking,
+		Passed:   ctx.bep20TotalSupply.Cmp(maxBep2Supply) <= 0,
+		Observed: ctx.bep20TotalSupply.String(),
+		Expected: fmt.Sprintf("<= %s", maxBep2Supply.String()),
+	}
+	if !finding.Passed {
+		finding.Message = "BEP20 total supply exceeds the max BEP2 allowance"
+		finding.Suggestion = "please mange your BEP20 token total supply, e.g., burn tokens"
+	}
+	return finding, nil
+}
+
+func checkTotalSupplyMatch(ctx *preCheckContext) (*types.Finding, error) {
+	amount, err := decimal.NewFromString(ctx.bep2Token.TotalSupply)
+	if err != nil {
+		return nil, err
+	}
+	amount = amount.Shift(constValue.BcDecimals)
+	finding := &types.Finding{
+		ID:       "TOTAL_SUPPLY_MATCH",
+		Title:    "Checking BEP20 total supply and BEP2 total supply match or not",
+		Severity: types.SeverityBlocking,
+		Observed: ctx.bep20TotalSupply.String(),
+	}
+	bep2Supply, err := utils.ConvertToBEP20Amount(amount.BigInt(), ctx.bep20Decimals.Int64(), utils.RoundExact)
+	if err != nil {
+		finding.Expected = amount.String() + " in BEP2 units"
+		finding.Message = fmt.Sprintf("BEP2 total supply can not be represented with %d BEP20 decimals: %s", ctx.bep20Decimals.Int64(), err.Error())
+		finding.Suggestion = "please use more BEP20 decimals or burn the BEP2 tokens below the BEP20 precision"
+		return finding, nil
+	}
+	finding.Passed = ctx.bep20TotalSupply.Cmp(bep2Supply) == 0
+	finding.Expected = bep2Supply.String()
+	if !finding.Passed {
+		finding.Message = "BEP20 total supply and BEP2 total supply do not match"
+		finding.Suggestion = "please mange your BEP20/BEP2 token total supply, e.g., burn tokens, mint tokens, " +
+			"to make use they are equal using different decimals"
+	}
+	return finding, nil
+}
+
+func checkGetOwner(ctx *preCheckContext) (*types.Finding, error) {
+	owner, err := ctx.bep20Instance.GetOwner(utils.GetCallOpts())
+	finding := &types.Finding{
+		ID:       "BEP20_GET_OWNER",
+		Title:    fmt.Sprintf("Checking BEP20 has getOwner function or not: %s", ctx.bep20Symbol),
+		Severity: types.SeverityBlocking,
+		Passed:   err == nil,
+		Expected: "getOwner() implemented",
+	}
+	if !finding.Passed {
+		finding.Message = "BEP20 does not implement getOwner funtion"
+		finding.Observed = err.Error()
+		finding.Suggestion = "please upgrade your contract to follow BEP20 standards, i.e., adding getOwner function"
+	} else {
+		finding.Observed = owner.String()
+	}
+	return finding, nil
+}
+
+func checkBindPackageRecord(ctx *preCheckContext) (*types.Finding, error) {
+	tokenManagerInstance, err := tokenmanager.NewTokenmanager(constValue.TokenManagerContractAddr, ctx.ethClient)
+	if err != nil {
+		return nil, err
+	}
+	var symbolKey [32]byte
+	copy(symbolKey[:], ctx.bep2Symbol)
+	record, err := tokenManagerInstance.BindPackageRecord(utils.GetCallOpts(), symbolKey)
+	if err != nil {
+		return nil, err
+	}
+	finding := &types.Finding{
+		ID:       "BIND_PACKAGE_RECORD",
+		Title:    fmt.Sprintf("Checking pending bind package of %s", ctx.bep2Symbol),
+		Severity: types.SeverityBlocking,
+		Passed:   true,
+		Expected: ctx.bep20ContractAddr.String(),
+	}
+	if record.ContractAddr == (common.Address{}) {
+		finding.Severity = types.SeverityInfo
+		finding.Observed = "no pending bind package"
+		finding.Message = "no bind transaction has been sent on Beacon Chain yet"
+		return finding, nil
+	}
+	finding.Observed = record.ContractAddr.String()
+	expireTime := time.Unix(int64(record.ExpireTime), 0)
+	switch {
+	case record.ContractAddr != ctx.bep20ContractAddr:
+		finding.Passed = false
+		finding.Message = fmt.Sprintf("a pending bind package binds %s to another contract %s", ctx.bep2Symbol, record.ContractAddr.String())
+		finding.Suggestion = "reject the pending bind with the contract owner, or wait until it expires at " + expireTime.String()
+	case time.Now().After(expireTime):
+		finding.Passed = false
+		finding.Message = fmt.Sprintf("the pending bind package expired at %s", expireTime.String())
+		finding.Suggestion = "send a new bind transaction on Beacon Chain"
+	case int64(record.Bep20Decimals) != ctx.bep20Decimals.Int64():
+		finding.Passed = false
+		finding.Observed = fmt.Sprintf("decimals %d", record.Bep20Decimals)
+		finding.Expected = fmt.Sprintf("decimals %s", ctx.bep20Decimals.String())
+		finding.Message = "the contract decimals in the bind transaction do not match the BEP20 decimals"
+		finding.Suggestion = "reject the pending bind and send a new bind transaction with the right contract decimals"
+	}
+	return finding, nil
+}
+
+func checkApproveAmount(ctx *preCheckContext) (*types.Finding, error) {
+	finding := &types.Finding{
+		ID:       "APPROVE_AMOUNT",
+		Title:    "Computing the amount to approve to TokenManager",
+		Severity: types.SeverityInfo,
+		Passed:   ctx.lockAmount != nil,
+	}
+	if ctx.bep20PeggyAmount != nil && ctx.bep20PeggyAmount.Cmp(ctx.bep20TotalSupply) > 0 {
+		finding.Severity = types.SeverityBlocking
+		finding.Observed = ctx.bep20PeggyAmount.String()
+		finding.Expected = fmt.Sprintf("<= %s", ctx.bep20TotalSupply.String())
+		finding.Message = fmt.Sprintf("peggy amount %s exceeds total supply %s", ctx.bep20PeggyAmount.String(), ctx.bep20TotalSupply.String())
+		finding.Suggestion = "pass a --peggy-amount no larger than the total supply, in the bind transaction as well"
+		return finding, nil
+	}
+	if ctx.lockAmount == nil {
+		finding.Severity = types.SeverityWarning
+		finding.Message = "cannot compute the lock amount, no bind package is pending"
+		finding.Suggestion = "pass --peggy-amount with the peggy amount of the bind transaction"
+		return finding, nil
+	}
+	finding.Observed = ctx.lockAmount.String()
+	finding.Message = fmt.Sprintf("approve %s to %s before approveBind", ctx.lockAmount.String(), constValue.TokenManagerContractAddr.String())
+	return finding, nil
+}
+
+func checkOwnerBalance(ctx *preCheckContext) (*types.Finding, error) {
+	finding := &types.Finding{
+		ID:       "OWNER_BEP20_BALANCE",
+		Title:    "Checking the BEP20 owner holds enough tokens to lock",
+		Severity: types.SeverityBlocking,
+	}
+	if ctx.bep20Owner == nil || ctx.lockAmount == nil {
+		finding.Severity = types.SeverityWarning
+		finding.Message = "skipped, the BEP20 owner or the lock amount is unknown"
+		finding.Suggestion = "fix the getOwner and approve amount findings first"
+		return finding, nil
+	}
+	balance, err := ctx.bep20Instance.BalanceOf(utils.GetCallOpts(), *ctx.bep20Owner)
+	if err != nil {
+		return nil, err
+	}
+	finding.Passed = balance.Cmp(ctx.lockAmount) >= 0
+	finding.Observed = balance.String()
+	finding.Expected = fmt.Sprintf(">= %s", ctx.lockAmount.String())
+	if !finding.Passed {
+		finding.Message = fmt.Sprintf("the BEP20 owner %s holds less than total supply minus peggy amount", ctx.bep20Owner.String())
+		finding.Suggestion = "transfer the missing BEP20 tokens to the owner before approveBind"
+	}
+	return finding, nil
+}
+
+func checkOwnerAllowance(ctx *preCheckContext) (*types.Finding, error) {
+	finding := &types.Finding{
+		ID:       "OWNER_ALLOWANCE",
+		Title:    "Checking the existing allowance of the BEP20 owner to TokenManager",
+		Severity: types.SeverityWarning,
+		Passed:   true,
+	}
+	if ctx.bep20Owner == nil {
+		finding.Message = "skipped, the BEP20 owner is unknown"
+		return finding, nil
+	}
+	allowance, err := ctx.bep20Instance.Allowance(utils.GetCallOpts(), *ctx.bep20Owner, constValue.TokenManagerContractAddr)
+	if err != nil {
+		return nil, err
+	}
+	finding.Observed = allowance.String()
+	if ctx.lockAmount != nil {
+		finding.Expected = ctx.lockAmount.String()
+		finding.Passed = allowance.Sign() == 0 || allowance.Cmp(ctx.lockAmount) == 0
+	}
+	if !finding.Passed {
+		finding.Message = "an earlier approval to TokenManager does not equal the lock amount"
+		finding.Suggestion = "approve exactly the lock amount again, approveBind locks the whole allowance"
+	}
+	return finding, nil
+}
+
+func checkTokenHubBalance(ctx *preCheckContext) (*types.Finding, error) {
+	balance, err := ctx.bep20Instance.BalanceOf(utils.GetCallOpts(), constValue.TokenHubContractAddr)
+	if err != nil {
+		return nil, err
+	}
+	finding := &types.Finding{
+		ID:       "TOKENHUB_BALANCE",
+		Title:    "Checking TokenHub does not hold the BEP20 token yet",
+		Severity: types.SeverityBlocking,
+		Passed:   balance.Sign() == 0,
+		Observed: balance.String(),
+		Expected: "0",
+	}
+	if !finding.Passed {
+		finding.Message = "TokenHub already holds the BEP20 token, approveBind fails with BIND_STATUS_TOO_MUCH_TOKENHUB_BALANCE"
+		finding.Suggestion = "please contact BNB chain support, tokens sent to TokenHub cannot be withdrawn by the owner"
+	}
+	return finding, nil
+}
+
+func checkOwnerBNB(ctx *preCheckContext) (*types.Finding, error) {
+	finding := &types.Finding{
+		ID:       "OWNER_BNB_BALANCE",
+		Title:    "Checking the BEP20 owner has enough BNB for the relay fee and gas",
+		Severity: types.SeverityBlocking,
+	}
+	if ctx.bep20Owner == nil {
+		finding.Severity = types.SeverityWarning
+		finding.Message = "skipped, the BEP20 owner is unknown"
+		return finding, nil
+	}
+	tokenhubInstance, err := tokenhub.NewTokenhub(constValue.TokenHubContractAddr, ctx.ethClient)
+	if err != nil {
+		return nil, err
+	}
+	relayFee, err := tokenhubInstance.GetMiniRelayFee(utils.GetCallOpts())
+	if err != nil {
+		return nil, err
+	}
+	balance, err := ctx.ethClient.BalanceAt(context.Background(), *ctx.bep20Owner, nil)
+	if err != nil {
+		return nil, err
+	}
+	// approve and approveBind are both sent with the default gas limit and gas price.
+	gasFee := new(big.Int).Mul(big.NewInt(2*constValue.DefaultGasLimit), big.NewInt(constValue.DefaultGasPrice))
+	required := new(big.Int).Add(relayFee, gasFee)
+	finding.Passed = balance.Cmp(required) >= 0
+	finding.Observed = balance.String()
+	finding.Expected = fmt.Sprintf(">= %s (relay fee %s + gas %s)", required.String(), relayFee.String(), gasFee.String())
+	if !finding.Passed {
+		finding.Message = fmt.Sprintf("the BEP20 owner %s cannot pay the relay fee and gas", ctx.bep20Owner.String())
+		finding.Suggestion = "transfer BNB to the BEP20 owner"
+	}
+	return finding, nil
+}
+
+func renderPreCheckReport(report *PreCheckReport, output string) error {
+	switch output {
+	case constValue.OutputJSON:
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case constValue.OutputTable:
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "ID\tSEVERITY\tRESULT\tOBSERVED\tEXPECTED")
+		for _, finding := range report.Findings {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", finding.ID, finding.Severity, findingResult(finding), finding.Observed, finding.Expected)
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+	case constValue.OutputMarkdown:
+		fmt.Printf("## preCheck %s / %s\n\n", report.Bep2Symbol, report.Bep20ContractAddr)
+		fmt.Printf("Bind limits from %s.\n\n", report.LimitsSource)
+		fmt.Println("| ID | Severity | Result | Observed | Expected | Suggestion |")
+		fmt.Println("| --- | --- | --- | --- | --- | --- |")
+		for _, finding := range report.Findings {
+			fmt.Printf("| %s | %s | %s | %s | %s | %s |\n", finding.ID, finding.Severity, findingResult(finding),
+				finding.Observed, finding.Expected, finding.Suggestion)
+		}
+	default:
+		fmt.Printf("Bind limits from %s\n", report.LimitsSource)
+		for idx, finding := range report.Findings {
+			fmt.Printf("\n%d. %s \n", idx+1, finding.Title)
+			if finding.Passed {
+				fmt.Println("Pass")
+				if len(finding.Message) != 0 {
+					fmt.Println(finding.Message)
+				}
+				continue
+			}
+			if finding.Severity == types.SeverityBlocking {
+				fmt.Printf("Cannot bind: %s\n", finding.Message)
+			} else {
+				fmt.Printf("Warning: %s\n", finding.Message)
+			}
+			if len(finding.Observed) != 0 || len(finding.Expected) != 0 {
+				fmt.Printf("Observed: %s, expected: %s\n", finding.Observed, finding.Expected)
+			}
+			fmt.Printf("Suggestion: %s\n", finding.Suggestion)
+		}
+		if report.CanBind {
+			fmt.Printf("\n You can bind the BEP2 and BEP20 tokens, please read README and take actions \n")
+		} else {
+			fmt.Printf("\n The BEP2 and BEP20 cannot bind, please take actions according the suggestions \n")
+		}
+	}
+	return nil
+}
+
+func findingResult(finding *types.Finding) string {
+	if finding.Passed {
+		return "pass"
+	}
+	return "fail"
+}
+
+// bindLimits are the governance-mutable limits TokenManager applies to a bind. Source tells whether they were read
+// from the system contracts or fell back to the built-in constants.
+type bindLimits struct {
+	MinSymbolLen       int
+	MaxSymbolLen       int
+	MaxBep2TotalSupply *big.Int
+	Source             string
+}
+
+func getBindLimits(ethClient *ethclient.Client) *bindLimits {
+	limits := &bindLimits{
+		MinSymbolLen:       constValue.BcMinSymbolLen,
+		MaxSymbolLen:       constValue.BcMaxSymbolLen,
+		MaxBep2TotalSupply: big.NewInt(constValue.BcMaxSupply),
+		Source:             "built-in constants",
+	}
+	minSymbolLen, maxSymbolLen, maxBep2TotalSupply, err := queryBindLimits(ethClient)
+	if err != nil {
+		limits.Source = fmt.Sprintf("built-in constants (failed to read system contracts: %s)", err.Error())
+		return limits
+	}
+	limits.MinSymbolLen = int(minSymbolLen)
+	limits.MaxSymbolLen = int(maxSymbolLen)
+	limits.MaxBep2TotalSupply = maxBep2TotalSupply
+	limits.Source = "TokenManager and TokenHub system contracts"
+	return limits
+}
+
+func queryBindLimits(ethClient *ethclient.Client) (uint8, uint8, *big.Int, error) {
+	tokenManagerInstance, err := tokenmanager.NewTokenmanager(constValue.TokenManagerContractAddr, ethClient)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	minSymbolLen, err := tokenManagerInstance.MINIMUMBEP20SYMBOLLEN(utils.GetCallOpts())
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	maxSymbolLen, err := tokenManagerInstance.MAXIMUMBEP20SYMBOLLEN(utils.GetCallOpts())
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	tokenhubInstance, err := tokenhub.NewTokenhub(constValue.TokenHubContractAddr, ethClient)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	maxBep2TotalSupply, err := tokenhubInstance.MAXBEP2TOTALSUPPLY(utils.GetCallOpts())
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	return minSymbolLen, maxSymbolLen, maxBep2TotalSupply, nil
+}
+
+func getBcTokenClient() (*utils.BcTokenClient, error) {
+	tokenFile := viper.GetString(constValue.BcTokenFile)
+	cachePath := viper.GetString(constValue.BcTokenCachePath)
+	cacheTTL := viper.GetDuration(constValue.BcTokenCacheTTL)
+	if len(tokenFile) != 0 {
+		return utils.NewBcTokenClient("", tokenFile, cachePath, cacheTTL), nil
+	}
+	apiUrl := viper.GetString(constValue.BcApiUrl)
+	if len(apiUrl) == 0 {
+		switch viper.GetString(constValue.NetworkType) {
+		case constValue.Mainnet:
+			apiUrl = constValue.BcMainnetApiUrl
+		case constValue.TestNet:
+			apiUrl = constValue.BcTestnetApiUrl
+		default:
+			return nil, fmt.Errorf("unsupported network type")
+		}
+	}
+	return utils.NewBcTokenClient(apiUrl, "", cachePath, cacheTTL), nil
+}
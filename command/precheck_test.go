@@ -0,0 +1,224 @@
+package command
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/contracts/bep20"
+	"github.com/binance-chain/token-bind-tool/contracts/tokenhub"
+	tokenmanager "github.com/binance-chain/token-bind-tool/contracts/tokenmanger"
+	"github.com/binance-chain/token-bind-tool/types"
+)
+
+func newTestPreCheckContext(bep2Symbol, bep20Symbol, bep2TotalSupply string, bep20TotalSupply *big.Int) *preCheckContext {
+	return &preCheckContext{
+		bep2Symbol:       bep2Symbol,
+		bep2Token:        &types.Bep2{Symbol: bep2Symbol, TotalSupply: bep2TotalSupply},
+		bep20Symbol:      bep20Symbol,
+		bep20TotalSupply: bep20TotalSupply,
+		bep20Decimals:    big.NewInt(18),
+		limits: &bindLimits{
+			MinSymbolLen:       constValue.BcMinSymbolLen,
+			MaxSymbolLen:       constValue.BcMaxSymbolLen,
+			MaxBep2TotalSupply: big.NewInt(constValue.BcMaxSupply),
+		},
+	}
+}
+
+func TestPreCheckSymbolFindings(t *testing.T) {
+	ctx := newTestPreCheckContext("ABC-123", "ABC", "1", big.NewInt(1))
+	finding, err := checkSymbolMatch(ctx)
+	require.NoError(t, err)
+	require.True(t, finding.Passed)
+
+	// Equal length symbols used to index out of range.
+	ctx = newTestPreCheckContext("ABCDEFG", "ABCDEFG", "1", big.NewInt(1))
+	finding, err = checkSymbolMatch(ctx)
+	require.NoError(t, err)
+	require.True(t, finding.Blocking())
+
+	ctx = newTestPreCheckContext("ABCDEFGHI-123", "ABCDEFGHI", "1", big.NewInt(1))
+	finding, err = checkSymbolLength(ctx)
+	require.NoError(t, err)
+	require.True(t, finding.Blocking())
+	require.Equal(t, "9", finding.Observed)
+	require.Equal(t, "2-8", finding.Expected)
+}
+
+func TestPreCheckSupplyFindings(t *testing.T) {
+	bep20TotalSupply, _ := new(big.Int).SetString("1500000000000000000000", 10)
+	ctx := newTestPreCheckContext("ABC-123", "ABC", "1500.00000000", bep20TotalSupply)
+	finding, err := checkTotalSupplyMatch(ctx)
+	require.NoError(t, err)
+	require.True(t, finding.Passed)
+
+	ctx = newTestPreCheckContext("ABC-123", "ABC", "1499", bep20TotalSupply)
+	finding, err = checkTotalSupplyMatch(ctx)
+	require.NoError(t, err)
+	require.True(t, finding.Blocking())
+
+	finding, err = checkMaxSupply(ctx)
+	require.NoError(t, err)
+	require.True(t, finding.Passed)
+
+	// With 2 decimals 1.23456789 used to be truncated to 1.23 and matched a BEP20 supply of 123.
+	ctx = newTestPreCheckContext("ABC-123", "ABC", "1.23456789", big.NewInt(123))
+	ctx.bep20Decimals = big.NewInt(2)
+	finding, err = checkTotalSupplyMatch(ctx)
+	require.NoError(t, err)
+	require.True(t, finding.Blocking())
+
+	ctx = newTestPreCheckContext("ABC-123", "ABC", "1", new(big.Int).Exp(big.NewInt(10), big.NewInt(77), nil))
+	ctx.bep20Decimals = big.NewInt(77)
+	finding, err = checkTotalSupplyMatch(ctx)
+	require.NoError(t, err)
+	require.True(t, finding.Passed)
+}
+
+// preCheckChain is the chain state the stub RPC of the BSC checks answers with.
+type preCheckChain struct {
+	record    []interface{}
+	balances  map[common.Address]*big.Int
+	allowance *big.Int
+	relayFee  *big.Int
+	ownerBNB  *big.Int
+}
+
+func newPreCheckChainContext(t *testing.T, chain *preCheckChain) (*preCheckContext, *stubRPC) {
+	abis := make(map[common.Address]*abi.ABI)
+	for addr, abiJSON := range map[common.Address]string{
+		constValue.TokenManagerContractAddr: tokenmanager.TokenmanagerABI,
+		constValue.TokenHubContractAddr:     tokenhub.TokenhubABI,
+		testBep20Addr:                       bep20.Bep20ABI,
+	} {
+		parsed, err := abi.JSON(strings.NewReader(abiJSON))
+		require.NoError(t, err)
+		abis[addr] = &parsed
+	}
+	stub := newStubRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		if method == "eth_getBalance" {
+			return hexutil.EncodeBig(chain.ownerBNB), nil
+		}
+		require.Equal(t, "eth_call", method)
+		var call struct {
+			To   common.Address `json:"to"`
+			Data hexutil.Bytes  `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(params[0], &call))
+		abiMethod, err := abis[call.To].MethodById(call.Data)
+		require.NoError(t, err)
+		var outputs []interface{}
+		switch abiMethod.Name {
+		case "bindPackageRecord":
+			outputs = chain.record
+		case "balanceOf":
+			args, err := abiMethod.Inputs.UnpackValues(call.Data[4:])
+			require.NoError(t, err)
+			balance, ok := chain.balances[args[0].(common.Address)]
+			if !ok {
+				balance = big.NewInt(0)
+			}
+			outputs = []interface{}{balance}
+		case "allowance":
+			outputs = []interface{}{chain.allowance}
+		case "getMiniRelayFee":
+			outputs = []interface{}{chain.relayFee}
+		default:
+			t.Fatalf("unexpected call %s", abiMethod.Name)
+		}
+		data, err := abiMethod.Outputs.Pack(outputs...)
+		require.NoError(t, err)
+		return hexutil.Encode(data), nil
+	})
+	ethClient := stub.ethClient(t)
+	bep20Instance, err := bep20.NewBep20(testBep20Addr, ethClient)
+	require.NoError(t, err)
+	ctx := newTestPreCheckContext("ABC-123", "ABC", "1000", big.NewInt(1000))
+	ctx.ethClient = ethClient
+	ctx.bep20ContractAddr = testBep20Addr
+	ctx.bep20Instance = bep20Instance
+	ctx.bep20Owner = &testOwnerAddr
+	ctx.lockAmount = big.NewInt(600)
+	return ctx, stub
+}
+
+var (
+	testBep20Addr = common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	testOwnerAddr = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+)
+
+func testBindPackageRecord(contractAddr common.Address, decimals uint8, expireTime time.Time) []interface{} {
+	var symbol [32]byte
+	copy(symbol[:], "ABC-123")
+	return []interface{}{uint8(0), symbol, contractAddr, big.NewInt(1000), big.NewInt(400), decimals, uint64(expireTime.Unix())}
+}
+
+func TestPreCheckChainFindings(t *testing.T) {
+	later := time.Now().Add(time.Hour)
+	otherAddr := common.HexToAddress("0x00000000000000000000000000000000000000c3")
+	cases := []struct {
+		name     string
+		check    preCheckFunc
+		chain    preCheckChain
+		setup    func(ctx *preCheckContext)
+		passed   bool
+		severity string
+		observed string
+	}{
+		{"no bind package", checkBindPackageRecord, preCheckChain{record: testBindPackageRecord(common.Address{}, 0, time.Unix(0, 0))}, nil, true, types.SeverityInfo, "no pending bind package"},
+		{"pending bind package", checkBindPackageRecord, preCheckChain{record: testBindPackageRecord(testBep20Addr, 18, later)}, nil, true, types.SeverityBlocking, testBep20Addr.String()},
+		{"bind package of another contract", checkBindPackageRecord, preCheckChain{record: testBindPackageRecord(otherAddr, 18, later)}, nil, false, types.SeverityBlocking, otherAddr.String()},
+		{"expired bind package", checkBindPackageRecord, preCheckChain{record: testBindPackageRecord(testBep20Addr, 18, time.Now().Add(-time.Hour))}, nil, false, types.SeverityBlocking, testBep20Addr.String()},
+		{"bind package decimals mismatch", checkBindPackageRecord, preCheckChain{record: testBindPackageRecord(testBep20Addr, 8, later)}, nil, false, types.SeverityBlocking, "decimals 8"},
+
+		{"approve amount", checkApproveAmount, preCheckChain{}, nil, true, types.SeverityInfo, "600"},
+		{"unknown lock amount", checkApproveAmount, preCheckChain{}, func(ctx *preCheckContext) { ctx.lockAmount = nil }, false, types.SeverityWarning, ""},
+		{"peggy amount exceeds total supply", checkApproveAmount, preCheckChain{}, func(ctx *preCheckContext) {
+			ctx.lockAmount = nil
+			ctx.bep20PeggyAmount = big.NewInt(1001)
+		}, false, types.SeverityBlocking, "1001"},
+
+		{"owner holds the lock amount", checkOwnerBalance, preCheckChain{balances: map[common.Address]*big.Int{testOwnerAddr: big.NewInt(600)}}, nil, true, types.SeverityBlocking, "600"},
+		{"owner holds less than the lock amount", checkOwnerBalance, preCheckChain{balances: map[common.Address]*big.Int{testOwnerAddr: big.NewInt(599)}}, nil, false, types.SeverityBlocking, "599"},
+		{"owner balance with unknown owner", checkOwnerBalance, preCheckChain{}, func(ctx *preCheckContext) { ctx.bep20Owner = nil }, false, types.SeverityWarning, ""},
+
+		{"no allowance", checkOwnerAllowance, preCheckChain{allowance: big.NewInt(0)}, nil, true, types.SeverityWarning, "0"},
+		{"allowance of the lock amount", checkOwnerAllowance, preCheckChain{allowance: big.NewInt(600)}, nil, true, types.SeverityWarning, "600"},
+		{"allowance of another amount", checkOwnerAllowance, preCheckChain{allowance: big.NewInt(100)}, nil, false, types.SeverityWarning, "100"},
+		{"allowance with unknown owner", checkOwnerAllowance, preCheckChain{}, func(ctx *preCheckContext) { ctx.bep20Owner = nil }, true, types.SeverityWarning, ""},
+
+		{"empty TokenHub", checkTokenHubBalance, preCheckChain{}, nil, true, types.SeverityBlocking, "0"},
+		{"TokenHub holds tokens", checkTokenHubBalance, preCheckChain{balances: map[common.Address]*big.Int{constValue.TokenHubContractAddr: big.NewInt(1)}}, nil, false, types.SeverityBlocking, "1"},
+
+		{"owner pays relay fee and gas", checkOwnerBNB, preCheckChain{relayFee: big.NewInt(1), ownerBNB: new(big.Int).Add(big.NewInt(1), big.NewInt(2*constValue.DefaultGasLimit*constValue.DefaultGasPrice))}, nil, true, types.SeverityBlocking, ""},
+		{"owner cannot pay relay fee and gas", checkOwnerBNB, preCheckChain{relayFee: big.NewInt(1), ownerBNB: big.NewInt(2 * constValue.DefaultGasLimit * constValue.DefaultGasPrice)}, nil, false, types.SeverityBlocking, ""},
+		{"owner BNB with unknown owner", checkOwnerBNB, preCheckChain{}, func(ctx *preCheckContext) { ctx.bep20Owner = nil }, false, types.SeverityWarning, ""},
+	}
+	for _, c := range cases {
+		chain := c.chain
+		ctx, stub := newPreCheckChainContext(t, &chain)
+		if c.setup != nil {
+			c.setup(ctx)
+		}
+		finding, err := c.check(ctx)
+		stub.Close()
+		require.NoError(t, err, c.name)
+		require.Equal(t, c.passed, finding.Passed, c.name)
+		require.Equal(t, c.severity, finding.Severity, c.name)
+		if len(c.observed) != 0 {
+			require.Equal(t, c.observed, finding.Observed, c.name)
+		}
+		if !finding.Passed {
+			require.NotEmpty(t, finding.Message, c.name)
+		}
+	}
+}
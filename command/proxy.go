@@ -0,0 +1,328 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+// proxyAdminCall is a call of the proxy admin to the proxy, with the check of the proxy once it is mined.
+type proxyAdminCall struct {
+	Step    string
+	Data    []byte
+	Summary *confirmSummary
+	Check   func(info *utils.ProxyInfo) error
+}
+
+// proxyFlag reads the proxy in --proxy-addr from its EIP-1967 slots. A proxy without admin can not be administered.
+func proxyFlag(ethClient *ethclient.Client) (*utils.ProxyInfo, error) {
+	proxy, err := contractAddrFlag(ethClient, constValue.ProxyAddr)
+	if err != nil {
+		return nil, err
+	}
+	info, err := utils.ReadProxyInfo(ethClient, proxy)
+	if err != nil {
+		return nil, err
+	}
+	if info.Admin == (common.Address{}) {
+		return nil, fmt.Errorf("--%s: %s is not an EIP-1967 proxy, its admin slot is empty", constValue.ProxyAddr, proxy.String())
+	}
+	return info, nil
+}
+
+func packProxyCall(method string, args ...interface{}) ([]byte, error) {
+	proxyABI, err := abi.JSON(strings.NewReader(constValue.UpgradeableProxyABI))
+	if err != nil {
+		return nil, err
+	}
+	return proxyABI.Pack(method, args...)
+}
+
+// sendProxyAdminCall sends call from the signer, which must be the admin of the proxy, then checks the proxy. With
+// --dry-run it prints the transaction instead.
+func sendProxyAdminCall(cmd *cobra.Command, ethClient *ethclient.Client, chainId *big.Int, info *utils.ProxyInfo, call *proxyAdminCall) error {
+	wallet, account, err := openSigner(chainId)
+	if err != nil {
+		return err
+	}
+	if err := checkProxyAdmin(info, account.Address); err != nil {
+		return err
+	}
+	if viper.GetBool(constValue.DryRun) {
+		plan, err := newDryRunPlan(ethClient, cmd.Name(), account.Address, chainId)
+		if err != nil {
+			return err
+		}
+		plan.add(call.Step, &info.Proxy, big.NewInt(0), call.Data, constValue.DefaultGasLimit)
+		return printDryRunPlan(plan)
+	}
+	err = confirmOperation(chainId, func() (*confirmSummary, error) {
+		call.Summary.add("Proxy", info.Proxy.String())
+		call.Summary.add("Admin", fmt.Sprintf("%s (signer)", info.Admin.String()))
+		call.Summary.add("Gas fee", maxGasFee(1))
+		return call.Summary, nil
+	})
+	if err != nil {
+		return err
+	}
+	data := hexutil.Bytes(call.Data)
+	tx, err := utils.SendTransactionFromLedger(ethClient, wallet, account, info.Proxy, big.NewInt(0), &data, chainId)
+	if err != nil {
+		return err
+	}
+	utils.PrintTxExplorerUrl(call.Step+" txHash", tx.Hash().String(), chainId)
+	utils.Sleep(10)
+	if err := checkTxReceipt(ethClient, tx, chainId); err != nil {
+		return err
+	}
+	updated, err := utils.ReadProxyInfo(ethClient, info.Proxy)
+	if err != nil {
+		return err
+	}
+	if err := call.Check(updated); err != nil {
+		return err
+	}
+	return printProxyInfo(updated)
+}
+
+// checkProxyAdmin refuses a signer which is not the admin of the proxy, the proxy would forward its call to the
+// implementation instead.
+func checkProxyAdmin(info *utils.ProxyInfo, signer common.Address) error {
+	if signer != info.Admin {
+		return fmt.Errorf("the signer %s is not the admin %s of proxy %s", signer.String(), info.Admin.String(), info.Proxy.String())
+	}
+	return nil
+}
+
+func printProxyInfo(info *utils.ProxyInfo) error {
+	if viper.GetString(constValue.Output) == constValue.OutputJSON {
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Println(fmt.Sprintf("Proxy: %s", info.Proxy.String()))
+	fmt.Println(fmt.Sprintf("Implementation: %s", info.Implementation.String()))
+	fmt.Println(fmt.Sprintf("Admin: %s", info.Admin.String()))
+	return nil
+}
+
+// implementationFlag parses --implementation, which must have code and differ from the current implementation.
+func implementationFlag(ethClient *ethclient.Client, info *utils.ProxyInfo) (common.Address, error) {
+	implementation, err := contractAddrFlag(ethClient, constValue.Implementation)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if implementation == info.Implementation {
+		return common.Address{}, fmt.Errorf("--%s: %s is already the implementation of proxy %s", constValue.Implementation, implementation.String(), info.Proxy.String())
+	}
+	return implementation, nil
+}
+
+func checkImplementation(implementation common.Address) func(info *utils.ProxyInfo) error {
+	return func(info *utils.ProxyInfo) error {
+		if info.Implementation != implementation {
+			return fmt.Errorf("the implementation of proxy %s is %s after the upgrade, expect %s", info.Proxy.String(), info.Implementation.String(), implementation.String())
+		}
+		return nil
+	}
+}
+
+func checkAdmin(newAdmin common.Address) func(info *utils.ProxyInfo) error {
+	return func(info *utils.ProxyInfo) error {
+		if info.Admin != newAdmin {
+			return fmt.Errorf("the admin of proxy %s is %s after changeAdmin, expect %s", info.Proxy.String(), info.Admin.String(), newAdmin.String())
+		}
+		return nil
+	}
+}
+
+func upgradeSummary(info *utils.ProxyInfo, implementation common.Address) *confirmSummary {
+	summary := &confirmSummary{Title: fmt.Sprintf("About to upgrade a proxy on %s", viper.GetString(constValue.NetworkType)), Recipient: implementation}
+	summary.add("Current implementation", info.Implementation.String())
+	summary.add("New implementation", implementation.String())
+	return summary
+}
+
+func ProxyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "Show and administer an upgradeable proxy, like the one deployCanonicalProxyContract deploys",
+	}
+	cmd.AddCommand(
+		ProxyShowCmd(),
+		ProxyChangeAdminCmd(),
+		ProxyUpgradeToCmd(),
+		ProxyUpgradeToAndCallCmd(),
+	)
+	return cmd
+}
+
+func ProxyShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show --proxy-addr {proxy address}",
+		Short: "Show the implementation and the admin of a proxy, read from its EIP-1967 storage slots",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ethClient, _, err := getEnv()
+			if err != nil {
+				return err
+			}
+			proxy, err := contractAddrFlag(ethClient, constValue.ProxyAddr)
+			if err != nil {
+				return err
+			}
+			info, err := utils.ReadProxyInfo(ethClient, proxy)
+			if err != nil {
+				return err
+			}
+			return printProxyInfo(info)
+		},
+	}
+	cmd.Flags().String(constValue.ProxyAddr, "", "proxy address, or \"@Name\" of its deployment record")
+	cmd.Flags().String(constValue.Output, constValue.OutputText, "output format, text or json")
+	return cmd
+}
+
+func ProxyChangeAdminCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "changeAdmin --proxy-addr {proxy address} --new-admin {new admin}",
+		Short: "Hand the admin of a proxy over to another address",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ethClient, chainId, err := getEnv()
+			if err != nil {
+				return err
+			}
+			info, err := proxyFlag(ethClient)
+			if err != nil {
+				return err
+			}
+			newAdmin, err := recipientAddrFlag(ethClient, constValue.NewAdmin)
+			if err != nil {
+				return err
+			}
+			if newAdmin == (common.Address{}) {
+				return fmt.Errorf("--%s: the new admin can not be the zero address", constValue.NewAdmin)
+			}
+			if newAdmin == info.Admin {
+				return fmt.Errorf("--%s: %s is already the admin of proxy %s", constValue.NewAdmin, newAdmin.String(), info.Proxy.String())
+			}
+			data, err := packProxyCall("changeAdmin", newAdmin)
+			if err != nil {
+				return err
+			}
+			summary := &confirmSummary{Title: fmt.Sprintf("About to change the admin of a proxy on %s", viper.GetString(constValue.NetworkType)), Recipient: newAdmin}
+			summary.add("New admin", newAdmin.String())
+			return sendProxyAdminCall(cmd, ethClient, chainId, info, &proxyAdminCall{
+				Step:    "change proxy admin",
+				Data:    data,
+				Summary: summary,
+				Check:   checkAdmin(newAdmin),
+			})
+		},
+	}
+	addProxyAdminFlags(cmd)
+	cmd.Flags().String(constValue.NewAdmin, "", "new proxy admin, bsc address")
+	return cmd
+}
+
+func ProxyUpgradeToCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgradeTo --proxy-addr {proxy address} --implementation {implementation address}",
+		Short: "Upgrade a proxy to a new implementation",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ethClient, chainId, err := getEnv()
+			if err != nil {
+				return err
+			}
+			info, err := proxyFlag(ethClient)
+			if err != nil {
+				return err
+			}
+			implementation, err := implementationFlag(ethClient, info)
+			if err != nil {
+				return err
+			}
+			data, err := packProxyCall("upgradeTo", implementation)
+			if err != nil {
+				return err
+			}
+			return sendProxyAdminCall(cmd, ethClient, chainId, info, &proxyAdminCall{
+				Step:    "upgrade proxy",
+				Data:    data,
+				Summary: upgradeSummary(info, implementation),
+				Check:   checkImplementation(implementation),
+			})
+		},
+	}
+	addProxyAdminFlags(cmd)
+	cmd.Flags().String(constValue.Implementation, "", "new implementation address, or \"@Name\" of its deployment record")
+	return cmd
+}
+
+func ProxyUpgradeToAndCallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgradeToAndCall --proxy-addr {proxy address} --implementation {implementation address} --call-data {hex calldata}",
+		Short: "Upgrade a proxy to a new implementation and call it through the proxy in the same transaction",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ethClient, chainId, err := getEnv()
+			if err != nil {
+				return err
+			}
+			info, err := proxyFlag(ethClient)
+			if err != nil {
+				return err
+			}
+			implementation, err := implementationFlag(ethClient, info)
+			if err != nil {
+				return err
+			}
+			callData, err := hexutil.Decode(viper.GetString(constValue.CallData))
+			if err != nil || len(callData) < 4 {
+				return fmt.Errorf("--%s: expect the 0x prefixed calldata of the call to the new implementation", constValue.CallData)
+			}
+			data, err := packProxyCall("upgradeToAndCall", implementation, callData)
+			if err != nil {
+				return err
+			}
+			summary := upgradeSummary(info, implementation)
+			if call, err := utils.DecodeCalldata(callData); err == nil {
+				summary.add("Call", call.String())
+			} else {
+				summary.add("Call", hexutil.Encode(callData))
+			}
+			return sendProxyAdminCall(cmd, ethClient, chainId, info, &proxyAdminCall{
+				Step:    "upgrade proxy and call",
+				Data:    data,
+				Summary: summary,
+				Check:   checkImplementation(implementation),
+			})
+		},
+	}
+	addProxyAdminFlags(cmd)
+	cmd.Flags().String(constValue.Implementation, "", "new implementation address, or \"@Name\" of its deployment record")
+	cmd.Flags().String(constValue.CallData, "", "0x prefixed calldata of the call to the new implementation, like an initializer")
+	return cmd
+}
+
+// addProxyAdminFlags adds the flags shared by the commands the proxy admin signs.
+func addProxyAdminFlags(cmd *cobra.Command) {
+	cmd.Flags().String(constValue.ProxyAddr, "", "proxy address, or \"@Name\" of its deployment record")
+	addSignerFlags(cmd)
+	cmd.Flags().Bool(constValue.Yes, false, "skip the typed confirmation, for scripted use")
+	cmd.Flags().Bool(constValue.ConfirmTestnet, false, "ask for the typed confirmation on testnet as well")
+	cmd.Flags().Bool(constValue.DryRun, false, "print the planned transaction and exit without signing")
+	cmd.Flags().String(constValue.Output, constValue.OutputText, "output format, text or json")
+}
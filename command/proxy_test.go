@@ -0,0 +1,101 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+// newProxyStubRPC answers eth_getCode with code for the addresses in code, and eth_getStorageAt from storage.
+func newProxyStubRPC(t *testing.T, code map[common.Address]bool, storage map[common.Address]map[common.Hash]common.Address) *stubRPC {
+	return newStubRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		var addr common.Address
+		require.NoError(t, json.Unmarshal(params[0], &addr))
+		switch method {
+		case "eth_getCode":
+			if code[addr] {
+				return "0x6080", nil
+			}
+			return "0x", nil
+		case "eth_getStorageAt":
+			var slot common.Hash
+			require.NoError(t, json.Unmarshal(params[1], &slot))
+			return hexutil.Encode(common.BytesToHash(storage[addr][slot].Bytes()).Bytes()), nil
+		}
+		return nil, fmt.Errorf("unexpected method %s", method)
+	})
+}
+
+func TestProxyFlags(t *testing.T) {
+	defer viper.Reset()
+	proxy := common.HexToAddress("0x00000000000000000000000000000000000000b1")
+	implementation := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	newImplementation := common.HexToAddress("0x00000000000000000000000000000000000000b3")
+	admin := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	notProxy := common.HexToAddress("0x00000000000000000000000000000000000000b4")
+	storage := map[common.Address]map[common.Hash]common.Address{
+		proxy: {utils.EIP1967ImplementationSlot: implementation, utils.EIP1967AdminSlot: admin},
+	}
+	stub := newProxyStubRPC(t, map[common.Address]bool{proxy: true, implementation: true, newImplementation: true, notProxy: true}, storage)
+	defer stub.Close()
+	ethClient := stub.ethClient(t)
+
+	viper.Set(constValue.ProxyAddr, notProxy.String())
+	_, err := proxyFlag(ethClient)
+	require.EqualError(t, err, fmt.Sprintf("--%s: %s is not an EIP-1967 proxy, its admin slot is empty", constValue.ProxyAddr, notProxy.String()))
+
+	viper.Set(constValue.ProxyAddr, proxy.String())
+	info, err := proxyFlag(ethClient)
+	require.NoError(t, err)
+	require.Equal(t, &utils.ProxyInfo{Proxy: proxy, Implementation: implementation, Admin: admin}, info)
+
+	require.NoError(t, checkProxyAdmin(info, admin))
+	err = checkProxyAdmin(info, newImplementation)
+	require.EqualError(t, err, fmt.Sprintf("the signer %s is not the admin %s of proxy %s", newImplementation.String(), admin.String(), proxy.String()))
+
+	viper.Set(constValue.Implementation, implementation.String())
+	_, err = implementationFlag(ethClient, info)
+	require.EqualError(t, err, fmt.Sprintf("--%s: %s is already the implementation of proxy %s", constValue.Implementation, implementation.String(), proxy.String()))
+	viper.Set(constValue.Implementation, admin.String())
+	_, err = implementationFlag(ethClient, info)
+	require.Error(t, err)
+	viper.Set(constValue.Implementation, newImplementation.String())
+	upgradeTo, err := implementationFlag(ethClient, info)
+	require.NoError(t, err)
+	require.Equal(t, newImplementation, upgradeTo)
+}
+
+func TestProxyChecks(t *testing.T) {
+	proxy := common.HexToAddress("0x00000000000000000000000000000000000000b1")
+	implementation := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	newImplementation := common.HexToAddress("0x00000000000000000000000000000000000000b3")
+	admin := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	newAdmin := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	storage := map[common.Address]map[common.Hash]common.Address{
+		proxy: {utils.EIP1967ImplementationSlot: implementation, utils.EIP1967AdminSlot: admin},
+	}
+	stub := newProxyStubRPC(t, map[common.Address]bool{proxy: true}, storage)
+	defer stub.Close()
+	ethClient := stub.ethClient(t)
+
+	// The transaction was mined but did not change the proxy.
+	updated, err := utils.ReadProxyInfo(ethClient, proxy)
+	require.NoError(t, err)
+	require.Error(t, checkImplementation(newImplementation)(updated))
+	require.Error(t, checkAdmin(newAdmin)(updated))
+
+	storage[proxy][utils.EIP1967ImplementationSlot] = newImplementation
+	storage[proxy][utils.EIP1967AdminSlot] = newAdmin
+	updated, err = utils.ReadProxyInfo(ethClient, proxy)
+	require.NoError(t, err)
+	require.NoError(t, checkImplementation(newImplementation)(updated))
+	require.NoError(t, checkAdmin(newAdmin)(updated))
+}
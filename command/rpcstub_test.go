@@ -0,0 +1,89 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+type stubRPCRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type stubRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *stubRPCError   `json:"error,omitempty"`
+}
+
+type stubRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// stubRPC is a JSON-RPC server which answers single and batch requests with handle. batches counts the batch
+// requests.
+type stubRPC struct {
+	server  *httptest.Server
+	batches int
+}
+
+func newStubRPC(t *testing.T, handle func(method string, params []json.RawMessage) (interface{}, error)) *stubRPC {
+	stub := &stubRPC{}
+	answer := func(req *stubRPCRequest) *stubRPCResponse {
+		resp := &stubRPCResponse{JSONRPC: "2.0", ID: req.ID}
+		result, err := handle(req.Method, req.Params)
+		if err != nil {
+			resp.Error = &stubRPCError{Code: 3, Message: err.Error()}
+		} else {
+			resp.Result = result
+		}
+		return resp
+	}
+	stub.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		var out interface{}
+		if len(body) != 0 && body[0] == '[' {
+			stub.batches++
+			var reqs []*stubRPCRequest
+			require.NoError(t, json.Unmarshal(body, &reqs))
+			resps := make([]*stubRPCResponse, 0, len(reqs))
+			for _, req := range reqs {
+				resps = append(resps, answer(req))
+			}
+			out = resps
+		} else {
+			var req stubRPCRequest
+			require.NoError(t, json.Unmarshal(body, &req))
+			out = answer(&req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(out))
+	}))
+	return stub
+}
+
+func (stub *stubRPC) Close() {
+	stub.server.Close()
+}
+
+func (stub *stubRPC) rpcClient(t *testing.T) *rpc.Client {
+	client, err := rpc.DialContext(context.Background(), stub.server.URL)
+	require.NoError(t, err)
+	return client
+}
+
+func (stub *stubRPC) ethClient(t *testing.T) *ethclient.Client {
+	return ethclient.NewClient(stub.rpcClient(t))
+}
@@ -0,0 +1,319 @@
+package command
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/contracts/bep20"
+	"github.com/binance-chain/token-bind-tool/contracts/ownable"
+	"github.com/binance-chain/token-bind-tool/contracts/tokenhub"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+const (
+	simulationBlockTag = "pending"
+	// slotProbeLimit is how many storage slots are tried to locate the owner, a balance or an allowance of a BEP20.
+	slotProbeLimit = 32
+)
+
+var (
+	allowanceProbeValue = new(big.Int).SetBytes(common.FromHex("0x746f6b656e2d62696e642d746f6f6c"))
+	// simulatedTotalSupply is the balance of the deployer the transfer of the total supply is replayed with, since the
+	// real total supply is only known once the constructor has run.
+	simulatedTotalSupply = new(big.Int).Exp(big.NewInt(10), big.NewInt(27), nil)
+)
+
+// SimulationStep is the outcome of one planned transaction replayed with eth_call. Nothing is broadcast.
+type SimulationStep struct {
+	Name         string
+	From         common.Address
+	To           *common.Address
+	Value        *big.Int
+	Success      bool
+	GasUsed      uint64
+	Return       string
+	RevertReason string
+	Note         string
+}
+
+// SimulateApproveBind replays approve, approveBind with the relay fee, the refund of the rest BEP20 balance and
+// transferOwnership from sender. refundTo and newOwner are optional. approveBind depends on the allowance set by
+// approve, so it runs with the allowance storage slot of the BEP20 overridden to lockAmount.
+func SimulateApproveBind(rpcUrl string, ethClient *ethclient.Client, sender common.Address, bep20ContractAddr common.Address, bep2Symbol string, lockAmount *big.Int, refundTo *common.Address, newOwner *common.Address) ([]*SimulationStep, error) {
+	bep20ABI, err := abi.JSON(strings.NewReader(bep20.Bep20ABI))
+	if err != nil {
+		return nil, err
+	}
+	tokenManagerABI, err := abi.JSON(strings.NewReader(constValue.TokenManagerABI))
+	if err != nil {
+		return nil, err
+	}
+	ownableABI, err := abi.JSON(strings.NewReader(ownable.OwnableABI))
+	if err != nil {
+		return nil, err
+	}
+	tokenhubInstance, err := tokenhub.NewTokenhub(constValue.TokenHubContractAddr, ethClient)
+	if err != nil {
+		return nil, err
+	}
+	relayFee, err := tokenhubInstance.GetMiniRelayFee(utils.GetCallOpts())
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []*SimulationStep
+
+	approveData, err := bep20ABI.Pack("approve", constValue.TokenManagerContractAddr, lockAmount)
+	if err != nil {
+		return nil, err
+	}
+	steps = append(steps, simulateStep(rpcUrl, "approve", sender, &bep20ContractAddr, big.NewInt(0), approveData, nil))
+
+	approveBindData, err := tokenManagerABI.Pack("approveBind", bep20ContractAddr, bep2Symbol)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := allowanceOverride(rpcUrl, bep20ABI, bep20ContractAddr, sender, constValue.TokenManagerContractAddr, lockAmount)
+	var note string
+	if err != nil {
+		note = fmt.Sprintf("allowance not overridden: %s", err.Error())
+	}
+	approveBindStep := simulateStep(rpcUrl, "approveBind", sender, &constValue.TokenManagerContractAddr, relayFee, approveBindData, overrides)
+	if approveBindStep.Success && approveBindStep.Return == hexutil.Encode(common.LeftPadBytes([]byte{0}, 32)) {
+		approveBindStep.Success = false
+		approveBindStep.RevertReason = "approveBind returned false, the bind would be rejected"
+	}
+	approveBindStep.Note = strings.TrimPrefix(approveBindStep.Note+"; "+note, "; ")
+	steps = append(steps, approveBindStep)
+
+	if refundTo != nil {
+		bep20Instance, err := bep20.NewBep20(bep20ContractAddr, ethClient)
+		if err != nil {
+			return nil, err
+		}
+		balance, err := bep20Instance.BalanceOf(utils.GetCallOpts(), sender)
+		if err != nil {
+			return nil, err
+		}
+		rest := new(big.Int).Sub(balance, lockAmount)
+		if rest.Sign() > 0 {
+			transferData, err := bep20ABI.Pack("transfer", *refundTo, rest)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, simulateStep(rpcUrl, "refund rest BEP20 balance", sender, &bep20ContractAddr, big.NewInt(0), transferData, nil))
+		}
+	}
+
+	if newOwner != nil {
+		transferOwnershipData, err := ownableABI.Pack("transferOwnership", *newOwner)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, simulateStep(rpcUrl, "transferOwnership", sender, &bep20ContractAddr, big.NewInt(0), transferOwnershipData, nil))
+	}
+	return steps, nil
+}
+
+// SimulateDeployAndTransfer replays the deployment of contractByteCodeStr from sender, then the transfer of the total
+// supply and transferOwnership to newOwner. eth_call does not carry the constructor state over, so both run against the
+// runtime code returned by the deployment, installed at the predicted address with a code override, with the owner
+// slot set to sender and the balance of sender set to simulatedTotalSupply. The slots are located by probing like the
+// allowance of approveBind.
+func SimulateDeployAndTransfer(rpcUrl string, ethClient *ethclient.Client, sender common.Address, contractByteCodeStr string, newOwner common.Address) ([]*SimulationStep, error) {
+	contractByteCode, err := hex.DecodeString(contractByteCodeStr)
+	if err != nil {
+		return nil, err
+	}
+	bep20ABI, err := abi.JSON(strings.NewReader(bep20.Bep20ABI))
+	if err != nil {
+		return nil, err
+	}
+	ownableABI, err := abi.JSON(strings.NewReader(ownable.OwnableABI))
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := ethClient.PendingNonceAt(context.Background(), sender)
+	if err != nil {
+		return nil, err
+	}
+	deployStep := simulateStep(rpcUrl, "deploy contract", sender, nil, big.NewInt(0), contractByteCode, nil)
+	contractAddr := crypto.CreateAddress(sender, nonce)
+	deployStep.Note = fmt.Sprintf("contract address %s", contractAddr.String())
+	steps := []*SimulationStep{deployStep}
+
+	transferData, err := bep20ABI.Pack("transfer", newOwner, simulatedTotalSupply)
+	if err != nil {
+		return nil, err
+	}
+	transferOwnershipData, err := ownableABI.Pack("transferOwnership", newOwner)
+	if err != nil {
+		return nil, err
+	}
+	if !deployStep.Success {
+		return append(steps,
+			&SimulationStep{Name: "transfer total supply", From: sender, To: &contractAddr, Value: big.NewInt(0), RevertReason: "the deployment failed"},
+			&SimulationStep{Name: "transferOwnership", From: sender, To: &contractAddr, Value: big.NewInt(0), RevertReason: "the deployment failed"},
+		), nil
+	}
+
+	runtimeCode := hexutil.Bytes(common.FromHex(deployStep.Return))
+	overrides := utils.StateOverride{contractAddr: {Code: &runtimeCode, StateDiff: make(map[common.Hash]common.Hash)}}
+	var notes []string
+	getOwnerData, err := bep20ABI.Pack("getOwner")
+	if err != nil {
+		return nil, err
+	}
+	ownerSlot, err := probeSlot(rpcUrl, contractAddr, &runtimeCode, getOwnerData, common.BytesToHash(sender.Bytes()), func(idx int64) common.Hash {
+		return common.BigToHash(big.NewInt(idx))
+	})
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("owner not overridden: %s", err.Error()))
+	} else {
+		overrides[contractAddr].StateDiff[ownerSlot] = common.BytesToHash(sender.Bytes())
+	}
+	balanceOfData, err := bep20ABI.Pack("balanceOf", sender)
+	if err != nil {
+		return nil, err
+	}
+	senderKey := common.BytesToHash(sender.Bytes())
+	balanceSlot, err := probeSlot(rpcUrl, contractAddr, &runtimeCode, balanceOfData, common.BigToHash(simulatedTotalSupply), func(idx int64) common.Hash {
+		return utils.MappingSlot(senderKey, common.BigToHash(big.NewInt(idx)))
+	})
+	if err != nil {
+		notes = append(notes, fmt.Sprintf("balance not overridden: %s", err.Error()))
+	} else {
+		overrides[contractAddr].StateDiff[balanceSlot] = common.BigToHash(simulatedTotalSupply)
+	}
+	note := strings.Join(append([]string{fmt.Sprintf("replayed on the deployed code with a total supply of %s held by the sender", simulatedTotalSupply.String())}, notes...), "; ")
+
+	transferStep := simulateStep(rpcUrl, "transfer total supply", sender, &contractAddr, big.NewInt(0), transferData, overrides)
+	transferStep.Note = strings.TrimPrefix(transferStep.Note+"; "+note, "; ")
+	transferOwnershipStep := simulateStep(rpcUrl, "transferOwnership", sender, &contractAddr, big.NewInt(0), transferOwnershipData, overrides)
+	transferOwnershipStep.Note = strings.TrimPrefix(transferOwnershipStep.Note+"; "+note, "; ")
+	return append(steps, transferStep, transferOwnershipStep), nil
+}
+
+func simulateStep(rpcUrl, name string, from common.Address, to *common.Address, value *big.Int, data []byte, overrides utils.StateOverride) *SimulationStep {
+	step := &SimulationStep{Name: name, From: from, To: to, Value: value}
+	args := &utils.CallArgs{From: from, To: to, Value: value, Data: data, Gas: constValue.DefaultGasLimit}
+	ret, err := utils.EthCall(rpcUrl, args, simulationBlockTag, overrides)
+	if err != nil {
+		step.RevertReason = err.Error()
+		return step
+	}
+	step.Success = true
+	// The return of a contract creation is the runtime code.
+	step.Return = hexutil.Encode(ret)
+	gasUsed, err := utils.EstimateGasWithOverrides(rpcUrl, args, simulationBlockTag, overrides, constValue.DefaultGasLimit)
+	if err != nil {
+		step.Note = fmt.Sprintf("gas estimation failed: %s", err.Error())
+	}
+	step.GasUsed = gasUsed
+	return step
+}
+
+// allowanceOverride locates the allowance mapping of token and returns an override which sets allowance(owner, spender)
+// to amount.
+func allowanceOverride(rpcUrl string, bep20ABI abi.ABI, token, owner, spender common.Address, amount *big.Int) (utils.StateOverride, error) {
+	allowanceData, err := bep20ABI.Pack("allowance", owner, spender)
+	if err != nil {
+		return nil, err
+	}
+	ownerKey := common.BytesToHash(owner.Bytes())
+	spenderKey := common.BytesToHash(spender.Bytes())
+	slot, err := probeSlot(rpcUrl, token, nil, allowanceData, common.BigToHash(allowanceProbeValue), func(idx int64) common.Hash {
+		return utils.MappingSlot(spenderKey, utils.MappingSlot(ownerKey, common.BigToHash(big.NewInt(idx))))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return utils.StateOverride{
+		token: {StateDiff: map[common.Hash]common.Hash{slot: common.BigToHash(amount)}},
+	}, nil
+}
+
+// probeSlot locates a storage slot of contract by writing probe into the candidate slotOf(idx) until the view call in
+// data returns it. code overrides the code of contract when it is not deployed yet.
+func probeSlot(rpcUrl string, contract common.Address, code *hexutil.Bytes, data []byte, probe common.Hash, slotOf func(idx int64) common.Hash) (common.Hash, error) {
+	for idx := int64(0); idx < slotProbeLimit; idx++ {
+		slot := slotOf(idx)
+		overrides := utils.StateOverride{
+			contract: {Code: code, StateDiff: map[common.Hash]common.Hash{slot: probe}},
+		}
+		ret, err := utils.EthCall(rpcUrl, &utils.CallArgs{To: &contract, Data: data}, simulationBlockTag, overrides)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if common.BytesToHash(ret) == probe {
+			return slot, nil
+		}
+	}
+	return common.Hash{}, fmt.Errorf("cannot locate the storage slot in the first %d storage slots", slotProbeLimit)
+}
+
+// simulateApproveBindCmd simulates approveBind from sender. With transferToOwner it also simulates the refund of the rest
+// BEP20 balance and the ownership transfer to bep20Owner, as approveBindAndTransferOwnership does.
+func simulateApproveBindCmd(ethClient *ethclient.Client, sender, bep20ContractAddr common.Address, bep2Symbol string, peggyAmount *big.Int, bep20Owner common.Address, transferToOwner bool) error {
+	rpcUrl, _, err := getRPCUrl()
+	if err != nil {
+		return err
+	}
+	lockAmount, err := getLockAmount(ethClient, bep20ContractAddr, bep2Symbol, peggyAmount)
+	if err != nil {
+		return err
+	}
+	fmt.Println(fmt.Sprintf("Lock amount %s:%s", lockAmount.String(), bep2Symbol))
+	var refundTo, newOwner *common.Address
+	if transferToOwner {
+		refundTo, newOwner = &bep20Owner, &bep20Owner
+	}
+	steps, err := SimulateApproveBind(rpcUrl, ethClient, sender, bep20ContractAddr, bep2Symbol, lockAmount, refundTo, newOwner)
+	if err != nil {
+		return err
+	}
+	return printSimulation(steps)
+}
+
+// printSimulation prints steps. It fails when a step reverts, and when a step is not simulated, as the outcome of
+// the planned transactions is then only partly known.
+func printSimulation(steps []*SimulationStep) error {
+	fmt.Println("Simulation, nothing is broadcast:")
+	failed, notSimulated := 0, 0
+	for idx, step := range steps {
+		to := "contract creation"
+		if step.To != nil {
+			to = step.To.String()
+		}
+		status := "not simulated"
+		if step.Success {
+			status = fmt.Sprintf("success, gas used %d", step.GasUsed)
+		} else if len(step.RevertReason) != 0 {
+			status = "failed: " + step.RevertReason
+			failed++
+		} else {
+			notSimulated++
+		}
+		fmt.Println(fmt.Sprintf("%d. %s from %s to %s, value %s: %s", idx+1, step.Name, step.From.String(), to, step.Value.String(), status))
+		if len(step.Note) != 0 {
+			fmt.Println(fmt.Sprintf("   %s", step.Note))
+		}
+	}
+	if failed != 0 {
+		return fmt.Errorf("simulation failed")
+	}
+	if notSimulated != 0 {
+		return fmt.Errorf("simulation incomplete, %d of %d steps were not simulated", notSimulated, len(steps))
+	}
+	return nil
+}
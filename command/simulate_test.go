@@ -0,0 +1,115 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/binance-chain/token-bind-tool/contracts/bep20"
+	"github.com/binance-chain/token-bind-tool/contracts/ownable"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+func TestPrintSimulation(t *testing.T) {
+	sender := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	contractAddr := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	deployed := &SimulationStep{Name: "deploy contract", From: sender, Value: big.NewInt(0), Success: true, GasUsed: 100}
+	transferred := &SimulationStep{Name: "transfer total supply", From: sender, To: &contractAddr, Value: big.NewInt(0), Success: true, GasUsed: 50}
+	notSimulated := &SimulationStep{Name: "transferOwnership", From: sender, To: &contractAddr, Value: big.NewInt(0), Note: "not simulated"}
+	reverted := &SimulationStep{Name: "approveBind", From: sender, To: &contractAddr, Value: big.NewInt(0), RevertReason: "execution reverted"}
+
+	require.NoError(t, printSimulation([]*SimulationStep{deployed, transferred}))
+
+	err := printSimulation([]*SimulationStep{deployed, notSimulated, notSimulated})
+	require.EqualError(t, err, "simulation incomplete, 2 of 3 steps were not simulated")
+
+	err = printSimulation([]*SimulationStep{deployed, reverted, notSimulated})
+	require.EqualError(t, err, "simulation failed")
+}
+
+// newTokenStubRPC emulates the deployment of a token whose owner is in slot 0 and balances in the mapping at slot 1,
+// and which only exists through the state overrides of eth_call.
+func newTokenStubRPC(t *testing.T, runtimeCode string) *stubRPC {
+	bep20ABI, err := abi.JSON(strings.NewReader(bep20.Bep20ABI))
+	require.NoError(t, err)
+	ownableABI, err := abi.JSON(strings.NewReader(ownable.OwnableABI))
+	require.NoError(t, err)
+	return newStubRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		switch method {
+		case "eth_getTransactionCount":
+			return "0x0", nil
+		case "eth_call":
+		default:
+			return nil, fmt.Errorf("unexpected method %s", method)
+		}
+		var call struct {
+			From common.Address  `json:"from"`
+			To   *common.Address `json:"to"`
+			Data hexutil.Bytes   `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(params[0], &call))
+		if call.To == nil {
+			return runtimeCode, nil
+		}
+		var overrides utils.StateOverride
+		if len(params) > 2 {
+			require.NoError(t, json.Unmarshal(params[2], &overrides))
+		}
+		account, ok := overrides[*call.To]
+		if !ok || account.Code == nil || hexutil.Encode(*account.Code) != runtimeCode {
+			return "0x", nil
+		}
+		storage := func(slot common.Hash) common.Hash {
+			return account.StateDiff[slot]
+		}
+		balanceSlot := func(addr common.Address) common.Hash {
+			return utils.MappingSlot(common.BytesToHash(addr.Bytes()), common.BigToHash(big.NewInt(1)))
+		}
+		abiMethod, err := bep20ABI.MethodById(call.Data)
+		if err != nil {
+			abiMethod, err = ownableABI.MethodById(call.Data)
+		}
+		require.NoError(t, err)
+		args, err := abiMethod.Inputs.UnpackValues(call.Data[4:])
+		require.NoError(t, err)
+		switch abiMethod.Name {
+		case "getOwner":
+			return hexutil.Encode(storage(common.Hash{}).Bytes()), nil
+		case "balanceOf":
+			return hexutil.Encode(storage(balanceSlot(args[0].(common.Address))).Bytes()), nil
+		case "transfer":
+			if storage(balanceSlot(call.From)).Big().Cmp(args[1].(*big.Int)) < 0 {
+				return nil, fmt.Errorf("execution reverted")
+			}
+		case "transferOwnership":
+			if common.BytesToAddress(storage(common.Hash{}).Bytes()) != call.From {
+				return nil, fmt.Errorf("execution reverted")
+			}
+		}
+		return hexutil.Encode(common.LeftPadBytes([]byte{1}, 32)), nil
+	})
+}
+
+func TestSimulateDeployAndTransfer(t *testing.T) {
+	sender := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	newOwner := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	stub := newTokenStubRPC(t, "0x6080604052")
+	defer stub.Close()
+
+	steps, err := SimulateDeployAndTransfer(stub.server.URL, stub.ethClient(t), sender, "60806040", newOwner)
+	require.NoError(t, err)
+	require.Len(t, steps, 3)
+	for _, step := range steps {
+		require.True(t, step.Success, "%s: %s %s", step.Name, step.RevertReason, step.Note)
+	}
+	require.Equal(t, crypto.CreateAddress(sender, 0), *steps[1].To)
+	require.NoError(t, printSimulation(steps))
+}
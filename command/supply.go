@@ -0,0 +1,266 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/contracts/bep20"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+// supplyToken is the state of a canonical BEP20 token which mint and burn check.
+type supplyToken struct {
+	Addr        common.Address
+	Symbol      string
+	Decimals    int64
+	TotalSupply *big.Int
+	Owner       common.Address
+	Mintable    bool
+}
+
+func readSupplyToken(ethClient *ethclient.Client, canonicalABI abi.ABI, addr common.Address) (*supplyToken, error) {
+	bep20Instance, err := bep20.NewBep20(addr, ethClient)
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := bep20Instance.Symbol(utils.GetCallOpts())
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := bep20Instance.Decimals(utils.GetCallOpts())
+	if err != nil {
+		return nil, err
+	}
+	totalSupply, err := bep20Instance.TotalSupply(utils.GetCallOpts())
+	if err != nil {
+		return nil, err
+	}
+	owner, err := bep20Instance.GetOwner(utils.GetCallOpts())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the owner of %s: %s", addr.String(), err.Error())
+	}
+	token := &supplyToken{Addr: addr, Symbol: symbol, Decimals: decimals.Int64(), TotalSupply: totalSupply, Owner: owner}
+
+	data, err := canonicalABI.Pack("mintable")
+	if err != nil {
+		return nil, err
+	}
+	result, err := ethClient.CallContract(context.Background(), ethereum.CallMsg{To: &addr, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call mintable() of %s: %s", addr.String(), err.Error())
+	}
+	if err := canonicalABI.Unpack(&token.Mintable, "mintable", result); err != nil {
+		return nil, fmt.Errorf("%s has no mintable(), expect a canonical mintable BEP20 token: %s", addr.String(), err.Error())
+	}
+	return token, nil
+}
+
+// reconcileAmount is the BEP20 amount to mint or burn, as method says, which makes the BEP20 total supply of token
+// equal to the total supply of the BEP2 token in --bep2-symbol, along with that BEP2 total supply. The amount is nil
+// when the supplies already match, and an error when the other method is needed.
+func reconcileAmount(token *supplyToken, method string) (*big.Int, string, error) {
+	bep2Symbol := viper.GetString(constValue.BEP2Symbol)
+	if len(bep2Symbol) == 0 {
+		return nil, "", fmt.Errorf("--%s needs --%s", constValue.Reconcile, constValue.BEP2Symbol)
+	}
+	tokenClient, err := getBcTokenClient()
+	if err != nil {
+		return nil, "", err
+	}
+	// The amount is signed right away, a cached BEP2 total supply may be stale.
+	tokenClient.CacheTTL = 0
+	bep2Token, err := tokenClient.GetBep2Token(bep2Symbol)
+	if err != nil {
+		return nil, "", err
+	}
+	bep2Supply := fmt.Sprintf("%s %s", bep2Token.TotalSupply, bep2Symbol)
+	diff, err := utils.SupplyDifference(bep2Token.TotalSupply, token.TotalSupply, token.Decimals)
+	if err != nil {
+		return nil, "", err
+	}
+	switch {
+	case diff.Sign() == 0:
+		return nil, bep2Supply, nil
+	case diff.Sign() > 0 && method == "burn":
+		return nil, "", fmt.Errorf("the BEP20 total supply is %s %s below the BEP2 total supply %s, mint instead", utils.FormatUnits(diff, token.Decimals), token.Symbol, bep2Supply)
+	case diff.Sign() < 0 && method == "mint":
+		return nil, "", fmt.Errorf("the BEP20 total supply is %s %s above the BEP2 total supply %s, burn instead", utils.FormatUnits(new(big.Int).Neg(diff), token.Decimals), token.Symbol, bep2Supply)
+	}
+	return diff.Abs(diff), bep2Supply, nil
+}
+
+// changeSupply mints or burns, as method says, the amount in --amount, or with --reconcile the amount which brings the
+// BEP20 total supply back to the BEP2 total supply. The signer must be the owner of the token, and the token mintable
+// to mint.
+func changeSupply(cmd *cobra.Command, method string) error {
+	label := "Mint"
+	if method == "burn" {
+		label = "Burn"
+	}
+	ethClient, chainId, err := getEnv()
+	if err != nil {
+		return err
+	}
+	canonicalABI, err := abi.JSON(strings.NewReader(constValue.CanonicalUpgradeableBEP20))
+	if err != nil {
+		return err
+	}
+	tokenAddr, err := contractAddrFlag(ethClient, constValue.BEP20ContractAddr)
+	if err != nil {
+		return err
+	}
+	token, err := readSupplyToken(ethClient, canonicalABI, tokenAddr)
+	if err != nil {
+		return err
+	}
+	if method == "mint" && !token.Mintable {
+		return fmt.Errorf("%s %s is not mintable", token.Symbol, token.Addr.String())
+	}
+
+	amountStr := viper.GetString(constValue.Amount)
+	reconcile := viper.GetBool(constValue.Reconcile)
+	if (len(amountStr) == 0) == !reconcile {
+		return fmt.Errorf("expect exactly one of --%s and --%s", constValue.Amount, constValue.Reconcile)
+	}
+	var amount *big.Int
+	bep2Supply := ""
+	if reconcile {
+		amount, bep2Supply, err = reconcileAmount(token, method)
+		if err != nil {
+			return err
+		}
+		if amount == nil {
+			fmt.Println(fmt.Sprintf("The BEP20 total supply %s %s already matches the BEP2 total supply %s", utils.FormatUnits(token.TotalSupply, token.Decimals), token.Symbol, bep2Supply))
+			return nil
+		}
+	} else {
+		parsed, err := utils.ParseAmount(amountStr, token.Decimals, token.Symbol)
+		if err != nil {
+			return fmt.Errorf("--%s: %s", constValue.Amount, err.Error())
+		}
+		if parsed.Raw.Sign() == 0 {
+			return fmt.Errorf("--%s: the amount must not be zero", constValue.Amount)
+		}
+		amount = parsed.Raw
+	}
+	printNotice(fmt.Sprintf("%s amount: %s", label, (&utils.Amount{Raw: amount, Decimals: token.Decimals, Symbol: token.Symbol}).String()))
+
+	wallet, account, err := openSigner(chainId)
+	if err != nil {
+		return err
+	}
+	if account.Address != token.Owner {
+		return fmt.Errorf("the signer %s is not the owner %s of %s", account.Address.String(), token.Owner.String(), token.Symbol)
+	}
+	newSupply := new(big.Int).Add(token.TotalSupply, amount)
+	if method == "burn" {
+		bep20Instance, err := bep20.NewBep20(token.Addr, ethClient)
+		if err != nil {
+			return err
+		}
+		balance, err := bep20Instance.BalanceOf(utils.GetCallOpts(), account.Address)
+		if err != nil {
+			return err
+		}
+		if balance.Cmp(amount) < 0 {
+			return fmt.Errorf("the owner holds %s %s, less than the burn amount", utils.FormatUnits(balance, token.Decimals), token.Symbol)
+		}
+		newSupply.Sub(token.TotalSupply, amount)
+	}
+	data, err := canonicalABI.Pack(method, amount)
+	if err != nil {
+		return err
+	}
+	if viper.GetBool(constValue.DryRun) {
+		plan, err := newDryRunPlan(ethClient, cmd.Name(), account.Address, chainId)
+		if err != nil {
+			return err
+		}
+		plan.add(method, &token.Addr, big.NewInt(0), data, constValue.DefaultGasLimit)
+		return printDryRunPlan(plan)
+	}
+	err = confirmOperation(chainId, func() (*confirmSummary, error) {
+		summary := &confirmSummary{Title: fmt.Sprintf("About to %s BEP20 tokens on %s", method, viper.GetString(constValue.NetworkType)), Recipient: account.Address}
+		summary.add("BEP20 contract", fmt.Sprintf("%s (%s, %d decimals)", token.Addr.String(), token.Symbol, token.Decimals))
+		summary.add(label+" amount", fmt.Sprintf("%s %s", utils.FormatUnits(amount, token.Decimals), token.Symbol))
+		if method == "mint" {
+			summary.add("Minted to", fmt.Sprintf("%s (owner)", account.Address.String()))
+		} else {
+			summary.add("Burnt from", fmt.Sprintf("%s (owner)", account.Address.String()))
+		}
+		summary.add("Total supply", fmt.Sprintf("%s -> %s %s", utils.FormatUnits(token.TotalSupply, token.Decimals), utils.FormatUnits(newSupply, token.Decimals), token.Symbol))
+		if len(bep2Supply) != 0 {
+			summary.add("BEP2 total supply", bep2Supply)
+		}
+		summary.add("Gas fee", maxGasFee(1))
+		return summary, nil
+	})
+	if err != nil {
+		return err
+	}
+	hexData := hexutil.Bytes(data)
+	tx, err := utils.SendTransactionFromLedger(ethClient, wallet, account, token.Addr, big.NewInt(0), &hexData, chainId)
+	if err != nil {
+		return err
+	}
+	utils.PrintTxExplorerUrl(label+" txHash", tx.Hash().String(), chainId)
+	utils.Sleep(10)
+	if err := checkTxReceipt(ethClient, tx, chainId); err != nil {
+		return err
+	}
+	updated, err := readSupplyToken(ethClient, canonicalABI, token.Addr)
+	if err != nil {
+		return err
+	}
+	fmt.Println(fmt.Sprintf("Total supply: %s %s", utils.FormatUnits(updated.TotalSupply, updated.Decimals), updated.Symbol))
+	return nil
+}
+
+func MintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mint --bep20-contract-addr {bep20 contract address} --amount {amount}",
+		Short: "Mint canonical mintable BEP20 tokens to the owner, the amount in --amount or, with --reconcile, the amount which matches the BEP2 total supply",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return changeSupply(cmd, "mint")
+		},
+	}
+	addSupplyFlags(cmd)
+	return cmd
+}
+
+func BurnCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "burn --bep20-contract-addr {bep20 contract address} --amount {amount}",
+		Short: "Burn canonical BEP20 tokens of the owner, the amount in --amount or, with --reconcile, the amount which matches the BEP2 total supply",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return changeSupply(cmd, "burn")
+		},
+	}
+	addSupplyFlags(cmd)
+	return cmd
+}
+
+func addSupplyFlags(cmd *cobra.Command) {
+	cmd.Flags().String(constValue.BEP20ContractAddr, "", "bep20 contract address, or \"@Name\" of its deployment record")
+	cmd.Flags().String(constValue.Amount, "", "amount in whole BEP20 tokens like \"1.5M\" or \"1000 ABC\", or in the smallest unit like \"1e18 wei\"")
+	cmd.Flags().Bool(constValue.Reconcile, false, "use the amount which makes the BEP20 total supply equal to the BEP2 total supply, instead of --amount")
+	cmd.Flags().String(constValue.BEP2Symbol, "", "bep2 token symbol, with --reconcile")
+	cmd.Flags().String(constValue.BcApiUrl, "", "Beacon Chain api url, like https://dex.binance.org/api/v1, default to the api of the selected network")
+	cmd.Flags().String(constValue.BcTokenFile, "", "local json file with the BEP2 token list, used instead of the Beacon Chain api")
+	addSignerFlags(cmd)
+	cmd.Flags().Bool(constValue.Yes, false, "skip the typed confirmation, for scripted use")
+	cmd.Flags().Bool(constValue.ConfirmTestnet, false, "ask for the typed confirmation on testnet as well")
+	cmd.Flags().Bool(constValue.DryRun, false, "print the planned transaction and exit without signing")
+	cmd.Flags().String(constValue.Output, constValue.OutputText, "dry run output format, text or json")
+}
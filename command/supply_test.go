@@ -0,0 +1,133 @@
+package command
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/types"
+)
+
+func newSupplyStubRPC(t *testing.T, canonicalABI abi.ABI, totalSupply *big.Int) *stubRPC {
+	return newStubRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		require.Equal(t, "eth_call", method)
+		var call struct {
+			Data hexutil.Bytes `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(params[0], &call))
+		abiMethod, err := canonicalABI.MethodById(call.Data)
+		require.NoError(t, err)
+		var outputs []interface{}
+		switch abiMethod.Name {
+		case "symbol":
+			outputs = []interface{}{"MYT"}
+		case "decimals":
+			outputs = []interface{}{uint8(18)}
+		case "totalSupply":
+			outputs = []interface{}{totalSupply}
+		case "getOwner":
+			outputs = []interface{}{common.HexToAddress("0x00000000000000000000000000000000000000a1")}
+		case "mintable":
+			outputs = []interface{}{true}
+		}
+		data, err := abiMethod.Outputs.Pack(outputs...)
+		require.NoError(t, err)
+		return hexutil.Encode(data), nil
+	})
+}
+
+func TestReconcileAmount(t *testing.T) {
+	defer viper.Reset()
+	dir, err := ioutil.TempDir("", "supply")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "tokens.json")
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte(`[{"symbol": "MYT-000", "total_supply": "1000.00000000"}]`), 0644))
+	viper.Set(constValue.BcTokenFile, tokenFile)
+	viper.Set(constValue.BEP2Symbol, "MYT-000")
+
+	canonicalABI, err := abi.JSON(strings.NewReader(constValue.CanonicalUpgradeableBEP20))
+	require.NoError(t, err)
+	tokens := func(amount int64) *big.Int {
+		return new(big.Int).Mul(big.NewInt(amount), big.NewInt(1e18))
+	}
+	cases := []struct {
+		totalSupply *big.Int
+		method      string
+		amount      *big.Int
+		err         string
+	}{
+		{tokens(1000), "mint", nil, ""},
+		{tokens(1000), "burn", nil, ""},
+		{tokens(900), "mint", tokens(100), ""},
+		{tokens(900), "burn", nil, "the BEP20 total supply is 100 MYT below the BEP2 total supply 1000.00000000 MYT-000, mint instead"},
+		{tokens(1100), "burn", tokens(100), ""},
+		{tokens(1100), "mint", nil, "the BEP20 total supply is 100 MYT above the BEP2 total supply 1000.00000000 MYT-000, burn instead"},
+	}
+	for _, c := range cases {
+		stub := newSupplyStubRPC(t, canonicalABI, c.totalSupply)
+		token, err := readSupplyToken(stub.ethClient(t), canonicalABI, common.HexToAddress("0x00000000000000000000000000000000000000b2"))
+		stub.Close()
+		require.NoError(t, err)
+		require.True(t, token.Mintable)
+
+		amount, bep2Supply, err := reconcileAmount(token, c.method)
+		if len(c.err) != 0 {
+			require.EqualError(t, err, c.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, c.amount, amount, "%s %s", c.method, c.totalSupply.String())
+		require.Equal(t, "1000.00000000 MYT-000", bep2Supply)
+	}
+
+	viper.Set(constValue.BEP2Symbol, "")
+	_, _, err = reconcileAmount(&supplyToken{TotalSupply: tokens(1000), Decimals: 18}, "mint")
+	require.Error(t, err)
+}
+
+func TestReconcileAmountBypassesCache(t *testing.T) {
+	defer viper.Reset()
+	dir, err := ioutil.TempDir("", "supply")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	requests := 0
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.NoError(t, json.NewEncoder(w).Encode([]*types.Bep2{{Symbol: "MYT-000", TotalSupply: "1000.00000000"}}))
+	}))
+	defer api.Close()
+
+	// A fresh cache entry with a supply which has changed since.
+	cachePath := filepath.Join(dir, "cache.json")
+	cache := map[string]interface{}{
+		api.URL + "|MYT-000": map[string]interface{}{"token": &types.Bep2{Symbol: "MYT-000", TotalSupply: "900.00000000"}, "fetched_at": time.Now()},
+	}
+	data, err := json.Marshal(cache)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(cachePath, data, 0644))
+	viper.Set(constValue.BcApiUrl, api.URL)
+	viper.Set(constValue.BcTokenCachePath, cachePath)
+	viper.Set(constValue.BcTokenCacheTTL, 10*time.Minute)
+	viper.Set(constValue.BEP2Symbol, "MYT-000")
+
+	amount, bep2Supply, err := reconcileAmount(&supplyToken{Symbol: "MYT", TotalSupply: big.NewInt(0), Decimals: 8}, "mint")
+	require.NoError(t, err)
+	require.Equal(t, 1, requests)
+	require.Equal(t, "1000.00000000 MYT-000", bep2Supply)
+	require.Equal(t, big.NewInt(100000000000), amount)
+}
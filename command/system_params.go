@@ -0,0 +1,204 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/contracts/tokenhub"
+	tokenmanager "github.com/binance-chain/token-bind-tool/contracts/tokenmanger"
+)
+
+const (
+	tokenHubName     = "TokenHub"
+	tokenManagerName = "TokenManager"
+)
+
+// systemParamMethods lists the parameterless view methods read by systemParams, per system contract.
+var systemParamMethods = map[string][]string{
+	tokenHubName: {
+		"relayFee",
+		"getMiniRelayFee",
+		"INIT_MINIMUM_RELAY_FEE",
+		"MAX_BEP2_TOTAL_SUPPLY",
+		"MAX_GAS_FOR_CALLING_BEP20",
+		"MAX_GAS_FOR_TRANSFER_BNB",
+		"MINIMUM_BEP20_SYMBOL_LEN",
+		"MAXIMUM_BEP20_SYMBOL_LEN",
+		"TEN_DECIMALS",
+		"BEP2_TOKEN_DECIMALS",
+		"REWARD_UPPER_LIMIT",
+		"bscChainID",
+		"BIND_CHANNELID",
+		"TRANSFER_IN_CHANNELID",
+		"TRANSFER_OUT_CHANNELID",
+		"STAKING_CHANNELID",
+		"GOV_CHANNELID",
+		"SLASH_CHANNELID",
+		"CROSS_CHAIN_CONTRACT_ADDR",
+		"GOV_HUB_ADDR",
+		"INCENTIVIZE_ADDR",
+		"LIGHT_CLIENT_ADDR",
+		"RELAYERHUB_CONTRACT_ADDR",
+		"SLASH_CONTRACT_ADDR",
+		"SYSTEM_REWARD_ADDR",
+		"TOKEN_HUB_ADDR",
+		"TOKEN_MANAGER_ADDR",
+		"VALIDATOR_CONTRACT_ADDR",
+	},
+	tokenManagerName: {
+		"MINIMUM_BEP20_SYMBOL_LEN",
+		"MAXIMUM_BEP20_SYMBOL_LEN",
+		"TEN_DECIMALS",
+		"bscChainID",
+		"BIND_CHANNELID",
+		"TRANSFER_OUT_CHANNELID",
+		"CROSS_CHAIN_CONTRACT_ADDR",
+		"TOKEN_HUB_ADDR",
+		"TOKEN_MANAGER_ADDR",
+	},
+}
+
+// SystemParam is a single constant or parameter read from a system contract.
+type SystemParam struct {
+	Contract string      `json:"contract"`
+	Address  string      `json:"address"`
+	Name     string      `json:"name"`
+	Value    interface{} `json:"value"`
+	Error    string      `json:"error,omitempty"`
+}
+
+func SystemParamsCmd() *cobra.Command {
+	const flagAtBlock = "at-block"
+	cmd := &cobra.Command{
+		Use:   "systemParams",
+		Short: "Show the relay fee, bind limits, channel ids and related system contract addresses of TokenHub and TokenManager",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rpcClient, _, err := getRPCEnv()
+			if err != nil {
+				return err
+			}
+			output := viper.GetString(constValue.Output)
+			if output != constValue.OutputTable && output != constValue.OutputJSON {
+				return fmt.Errorf("unsupported output %s, expect %s or %s", output, constValue.OutputTable, constValue.OutputJSON)
+			}
+			blockTag := "latest"
+			if atBlock := viper.GetUint64(flagAtBlock); atBlock > 0 {
+				blockTag = hexutil.EncodeUint64(atBlock)
+			}
+			params, err := QuerySystemParams(rpcClient, blockTag)
+			if err != nil {
+				return err
+			}
+			if output == constValue.OutputJSON {
+				data, err := json.MarshalIndent(params, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(data))
+				return nil
+			}
+			writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "CONTRACT\tPARAMETER\tVALUE")
+			for _, param := range params {
+				value := fmt.Sprintf("%v", param.Value)
+				if len(param.Error) != 0 {
+					value = "error: " + param.Error
+				}
+				fmt.Fprintf(writer, "%s\t%s\t%s\n", param.Contract, param.Name, value)
+			}
+			return writer.Flush()
+		},
+	}
+	cmd.Flags().Uint64(flagAtBlock, 0, "read the parameters at this block height, 0 means the latest block")
+	cmd.Flags().String(constValue.Output, constValue.OutputTable, "output format, table or json")
+	return cmd
+}
+
+// QuerySystemParams reads all systemParamMethods in a single JSON-RPC batch request at blockTag.
+// A method which fails on chain is reported in SystemParam.Error instead of failing the whole query.
+func QuerySystemParams(rpcClient *rpc.Client, blockTag string) ([]*SystemParam, error) {
+	tokenHubABI, err := abi.JSON(strings.NewReader(tokenhub.TokenhubABI))
+	if err != nil {
+		return nil, err
+	}
+	tokenManagerABI, err := abi.JSON(strings.NewReader(tokenmanager.TokenmanagerABI))
+	if err != nil {
+		return nil, err
+	}
+	contracts := []struct {
+		name    string
+		address common.Address
+		abi     abi.ABI
+	}{
+		{tokenHubName, constValue.TokenHubContractAddr, tokenHubABI},
+		{tokenManagerName, constValue.TokenManagerContractAddr, tokenManagerABI},
+	}
+
+	var params []*SystemParam
+	var methods []abi.Method
+	var batch []rpc.BatchElem
+	for _, contract := range contracts {
+		for _, name := range systemParamMethods[contract.name] {
+			method, ok := contract.abi.Methods[name]
+			if !ok {
+				return nil, fmt.Errorf("method %s is not found in %s abi", name, contract.name)
+			}
+			data := hexutil.Bytes(method.ID())
+			callArgs := map[string]interface{}{
+				"to":   contract.address,
+				"data": data,
+			}
+			params = append(params, &SystemParam{Contract: contract.name, Address: contract.address.String(), Name: name})
+			methods = append(methods, method)
+			batch = append(batch, rpc.BatchElem{
+				Method: "eth_call",
+				Args:   []interface{}{callArgs, blockTag},
+				Result: new(hexutil.Bytes),
+			})
+		}
+	}
+
+	if err := rpcClient.BatchCallContext(context.Background(), batch); err != nil {
+		return nil, err
+	}
+	for idx, elem := range batch {
+		if elem.Error != nil {
+			params[idx].Error = elem.Error.Error()
+			continue
+		}
+		values, err := methods[idx].Outputs.UnpackValues(*elem.Result.(*hexutil.Bytes))
+		if err != nil {
+			params[idx].Error = err.Error()
+			continue
+		}
+		if len(values) == 1 {
+			params[idx].Value = formatSystemParamValue(values[0])
+		} else {
+			params[idx].Value = values
+		}
+	}
+	return params, nil
+}
+
+func formatSystemParamValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case common.Address:
+		return v.String()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,77 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/contracts/tokenhub"
+)
+
+func TestQuerySystemParams(t *testing.T) {
+	tokenHubABI, err := abi.JSON(strings.NewReader(tokenhub.TokenhubABI))
+	require.NoError(t, err)
+	relayFee := big.NewInt(10000000000000000)
+	var blockTags []string
+	stub := newStubRPC(t, func(method string, params []json.RawMessage) (interface{}, error) {
+		require.Equal(t, "eth_call", method)
+		var call struct {
+			To   common.Address `json:"to"`
+			Data hexutil.Bytes  `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(params[0], &call))
+		var blockTag string
+		require.NoError(t, json.Unmarshal(params[1], &blockTag))
+		blockTags = append(blockTags, blockTag)
+		if call.To != constValue.TokenHubContractAddr {
+			return hexutil.Encode(common.LeftPadBytes([]byte{1}, 32)), nil
+		}
+		abiMethod, err := tokenHubABI.MethodById(call.Data)
+		require.NoError(t, err)
+		switch abiMethod.Name {
+		case "relayFee":
+			data, err := abiMethod.Outputs.Pack(relayFee)
+			require.NoError(t, err)
+			return hexutil.Encode(data), nil
+		case "TOKEN_MANAGER_ADDR":
+			data, err := abiMethod.Outputs.Pack(constValue.TokenManagerContractAddr)
+			require.NoError(t, err)
+			return hexutil.Encode(data), nil
+		case "REWARD_UPPER_LIMIT":
+			return nil, fmt.Errorf("execution reverted")
+		case "GOV_CHANNELID":
+			return "0x", nil
+		}
+		return hexutil.Encode(common.LeftPadBytes([]byte{1}, 32)), nil
+	})
+	defer stub.Close()
+
+	params, err := QuerySystemParams(stub.rpcClient(t), "0x10")
+	require.NoError(t, err)
+	require.Equal(t, 1, stub.batches)
+	require.Len(t, params, len(systemParamMethods[tokenHubName])+len(systemParamMethods[tokenManagerName]))
+	for _, blockTag := range blockTags {
+		require.Equal(t, "0x10", blockTag)
+	}
+
+	byName := make(map[string]*SystemParam)
+	for _, param := range params {
+		byName[param.Contract+"."+param.Name] = param
+	}
+	require.Equal(t, relayFee.String(), byName["TokenHub.relayFee"].Value)
+	require.Equal(t, constValue.TokenManagerContractAddr.String(), byName["TokenHub.TOKEN_MANAGER_ADDR"].Value)
+	require.Equal(t, constValue.TokenHubContractAddr.String(), byName["TokenHub.relayFee"].Address)
+	require.Equal(t, "execution reverted", byName["TokenHub.REWARD_UPPER_LIMIT"].Error)
+	require.Nil(t, byName["TokenHub.REWARD_UPPER_LIMIT"].Value)
+	require.NotEmpty(t, byName["TokenHub.GOV_CHANNELID"].Error)
+	require.Equal(t, uint8(1), byName["TokenManager.BIND_CHANNELID"].Value)
+	require.Empty(t, byName["TokenManager.BIND_CHANNELID"].Error)
+}
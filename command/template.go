@@ -0,0 +1,226 @@
+package command
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/types"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+// templateToken holds the constructor arguments of the BEP20 template.
+type templateToken struct {
+	Name        string
+	Symbol      string
+	Decimals    uint8
+	TotalSupply *big.Int
+	Mintable    bool
+}
+
+// templateTokenFromBEP2 derives the constructor arguments from bep2Token: the BEP20 symbol is the prefix of the BEP2
+// symbol and the BEP2 total supply is converted to decimals, it must not lose precision.
+func templateTokenFromBEP2(bep2Token *types.Bep2, decimals uint8) (*templateToken, error) {
+	bep2Supply, err := utils.ParseAmount(bep2Token.TotalSupply, constValue.BcDecimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BEP2 total supply: %s", err.Error())
+	}
+	totalSupply, err := utils.ConvertToBEP20Amount(bep2Supply.Raw, int64(decimals), utils.RoundExact)
+	if err != nil {
+		return nil, fmt.Errorf("BEP2 total supply %s can not be represented with %d BEP20 decimals: %s", bep2Token.TotalSupply, decimals, err.Error())
+	}
+	return &templateToken{
+		Name:        bep2Token.Name,
+		Symbol:      strings.Split(bep2Token.Symbol, "-")[0],
+		Decimals:    decimals,
+		TotalSupply: totalSupply,
+		Mintable:    bep2Token.Mintable,
+	}, nil
+}
+
+// creationCode returns the BEP20 template byte code followed by the ABI encoded constructor arguments.
+func (token *templateToken) creationCode() (string, error) {
+	if len(constValue.BEP20TemplateBytesCode) == 0 {
+		return "", fmt.Errorf("the BEP20 template byte code is not generated in this build, run `make bep20-template` with solc 0.5.16")
+	}
+	templateABI, err := abi.JSON(strings.NewReader(constValue.BEP20TemplateABI))
+	if err != nil {
+		return "", err
+	}
+	constructorArgs, err := templateABI.Pack("", token.Name, token.Symbol, token.Decimals, token.TotalSupply, token.Mintable)
+	if err != nil {
+		return "", err
+	}
+	return constValue.BEP20TemplateBytesCode + hex.EncodeToString(constructorArgs), nil
+}
+
+// recordDeployment writes the deployment record of the token, named by its symbol.
+func (token *templateToken) recordDeployment(deployment *utils.Deployment, contractData string) error {
+	constructorArgs, err := hex.DecodeString(strings.TrimPrefix(contractData, constValue.BEP20TemplateBytesCode))
+	if err != nil {
+		return err
+	}
+	deployment.EncodedConstructorArgs = constructorArgs
+	deployment.ABI = json.RawMessage(constValue.BEP20TemplateABI)
+	return recordDeployment(deployment, token.Symbol)
+}
+
+func DeployBEP20TemplateCmd() *cobra.Command {
+	const (
+		flagName        = "name"
+		flagSymbol      = "symbol"
+		flagDecimals    = "decimals"
+		flagTotalSupply = "total-supply"
+		flagMintable    = "mintable"
+	)
+	cmd := &cobra.Command{
+		Use:   "deployBEP20Template --name {name} --symbol {symbol} --total-supply {total supply}",
+		Short: "Deploy the BEP20 template shipped with the tool, the total supply is minted to the temp account unless --bep20-owner is given",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			decimals := viper.GetInt(flagDecimals)
+			if decimals < 0 || decimals > utils.MaxBEP20Decimals {
+				return fmt.Errorf("decimals must be between 0 and %d", utils.MaxBEP20Decimals)
+			}
+			ethClient, chainId, err := getEnv()
+			if err != nil {
+				return err
+			}
+
+			token := &templateToken{
+				Name:     viper.GetString(flagName),
+				Symbol:   viper.GetString(flagSymbol),
+				Decimals: uint8(decimals),
+				Mintable: viper.GetBool(flagMintable),
+			}
+			bep2Symbol := viper.GetString(constValue.BEP2Symbol)
+			if len(bep2Symbol) != 0 {
+				tokenClient, err := getBcTokenClient()
+				if err != nil {
+					return err
+				}
+				bep2Token, err := tokenClient.GetBep2Token(bep2Symbol)
+				if err != nil {
+					return err
+				}
+				derived, err := templateTokenFromBEP2(bep2Token, uint8(decimals))
+				if err != nil {
+					return err
+				}
+				if len(token.Symbol) != 0 && token.Symbol != derived.Symbol {
+					return fmt.Errorf("--%s %s must be the prefix of the bep2 symbol %s", flagSymbol, token.Symbol, bep2Symbol)
+				}
+				token.Symbol = derived.Symbol
+				if len(token.Name) == 0 {
+					token.Name = derived.Name
+				}
+				if cmd.Flags().Changed(flagMintable) && token.Mintable != derived.Mintable {
+					printNotice(fmt.Sprintf("Warning: --%s %t differs from the mintable flag of %s", flagMintable, token.Mintable, bep2Symbol))
+				} else {
+					token.Mintable = derived.Mintable
+				}
+				token.TotalSupply = derived.TotalSupply
+			}
+			if len(token.Name) == 0 || len(token.Symbol) == 0 {
+				return fmt.Errorf("missing token name or symbol")
+			}
+
+			totalSupplyStr := viper.GetString(flagTotalSupply)
+			if len(totalSupplyStr) != 0 {
+				totalSupplyAmount, err := utils.ParseAmount(totalSupplyStr, int64(decimals), token.Symbol)
+				if err != nil {
+					return fmt.Errorf("--%s: %s", flagTotalSupply, err.Error())
+				}
+				if token.TotalSupply != nil && token.TotalSupply.Cmp(totalSupplyAmount.Raw) != 0 {
+					return fmt.Errorf("--%s %s does not match the total supply %s of %s", flagTotalSupply, totalSupplyStr, utils.FormatUnits(token.TotalSupply, int64(decimals)), bep2Symbol)
+				}
+				token.TotalSupply = totalSupplyAmount.Raw
+			}
+			if token.TotalSupply == nil {
+				return fmt.Errorf("missing total supply, set --%s or --%s", flagTotalSupply, constValue.BEP2Symbol)
+			}
+			totalSupply := &utils.Amount{Raw: token.TotalSupply, Decimals: int64(decimals), Symbol: token.Symbol}
+			printNotice(fmt.Sprintf("Total supply: %s", totalSupply.String()))
+
+			contractData, err := token.creationCode()
+			if err != nil {
+				return err
+			}
+
+			keystorePath := viper.GetString(constValue.KeystorePath)
+			keyStore, tempAccount, err := generateOrGetTempAccount(keystorePath, chainId)
+			if err != nil {
+				return err
+			}
+			withOwner := len(viper.GetString(constValue.BEP20Owner)) != 0
+			bep20Owner := tempAccount.Address
+			if withOwner {
+				bep20Owner, err = recipientAddrFlag(ethClient, constValue.BEP20Owner)
+				if err != nil {
+					return err
+				}
+			}
+
+			if viper.GetBool(constValue.DryRun) {
+				plan, err := newDryRunPlan(ethClient, cmd.Name(), tempAccount.Address, chainId)
+				if err != nil {
+					return err
+				}
+				if withOwner {
+					err = planDeployAndTransfer(plan, contractData, defaultDeployGas(), bep20Owner)
+				} else {
+					_, err = planDeploy(plan, contractData, defaultDeployGas())
+				}
+				if err != nil {
+					return err
+				}
+				return printDryRunPlan(plan)
+			}
+			if !withOwner {
+				deployment, err := DeployContractFromTempAccount(ethClient, keyStore, tempAccount, contractData, defaultDeployGas(), chainId)
+				if err != nil {
+					return err
+				}
+				if err := token.recordDeployment(deployment, contractData); err != nil {
+					return err
+				}
+				return printDeployments([]*utils.Deployment{deployment})
+			}
+			err = confirmOperation(chainId, func() (*confirmSummary, error) {
+				return deployAndTransferSummary(ethClient, tempAccount.Address, bep20Owner)
+			})
+			if err != nil {
+				return err
+			}
+			deployment, err := DeployContractFromTempAccount(ethClient, keyStore, tempAccount, contractData, defaultDeployGas(), chainId)
+			if err != nil {
+				return err
+			}
+			if err := token.recordDeployment(deployment, contractData); err != nil {
+				return err
+			}
+			return TransferTokenAndOwnership(ethClient, keyStore, tempAccount, bep20Owner, deployment.Address, chainId)
+		},
+	}
+	cmd.Flags().String(constValue.KeystorePath, constValue.BindKeystore, "keystore path")
+	cmd.Flags().String(flagName, "", "token name, default to the bep2 token name with --bep2-symbol")
+	cmd.Flags().String(flagSymbol, "", "token symbol, default to the prefix of --bep2-symbol")
+	cmd.Flags().Int(flagDecimals, 18, "token decimals")
+	cmd.Flags().String(flagTotalSupply, "", "total supply in whole tokens like \"1.5M\" or \"1000 ABC\", or in the smallest unit like \"1e24 wei\", derived from the bep2 token with --bep2-symbol")
+	cmd.Flags().Bool(flagMintable, false, "whether the owner can mint, default to the bep2 mintable flag with --bep2-symbol")
+	cmd.Flags().String(constValue.BEP2Symbol, "", "bep2 token symbol, derive the symbol, name, total supply and mintable flag from it")
+	cmd.Flags().String(constValue.BEP20Owner, "", "transfer the total supply and ownership to this account after the deployment")
+	cmd.Flags().String(constValue.BcApiUrl, "", "Beacon Chain api url, default to the api of the selected network")
+	cmd.Flags().String(constValue.BcTokenFile, "", "local json file with the BEP2 token list, used instead of the Beacon Chain api")
+	cmd.Flags().Bool(constValue.Yes, false, "skip the typed confirmation, for scripted use")
+	cmd.Flags().Bool(constValue.ConfirmTestnet, false, "ask for the typed confirmation on testnet as well")
+	cmd.Flags().Bool(constValue.DryRun, false, "print the planned transactions and exit without signing")
+	cmd.Flags().String(constValue.Output, constValue.OutputText, "output format, text or json, which prints the deployment record without --bep20-owner")
+	return cmd
+}
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/types"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+func TestTemplateTokenFromBEP2(t *testing.T) {
+	bep2Token := &types.Bep2{Name: "ABC Token", Symbol: "ABC-123", Mintable: true, TotalSupply: "1000.50000000"}
+
+	token, err := templateTokenFromBEP2(bep2Token, 18)
+	require.NoError(t, err)
+	require.Equal(t, "ABC", token.Symbol)
+	require.Equal(t, "ABC Token", token.Name)
+	require.True(t, token.Mintable)
+	expected, _ := new(big.Int).SetString("1000500000000000000000", 10)
+	require.Equal(t, expected, token.TotalSupply)
+
+	token, err = templateTokenFromBEP2(bep2Token, 1)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10005), token.TotalSupply)
+
+	_, err = templateTokenFromBEP2(bep2Token, 0)
+	require.Error(t, err)
+}
+
+func TestBEP20TemplateCreationCode(t *testing.T) {
+	if len(constValue.BEP20TemplateBytesCode) == 0 {
+		t.Skip("the BEP20 template byte code is not generated, run `make bep20-template` with solc 0.5.16")
+	}
+	_, err := hex.DecodeString(constValue.BEP20TemplateBytesCode)
+	require.NoError(t, err)
+
+	token := &templateToken{Name: "ABC Token", Symbol: "ABC", Decimals: 18, TotalSupply: big.NewInt(1000), Mintable: true}
+	contractData, err := token.creationCode()
+	require.NoError(t, err)
+	data, err := hex.DecodeString(contractData)
+	require.NoError(t, err)
+	call, err := utils.DecodeCreation(data)
+	require.NoError(t, err)
+	require.Equal(t, "BEP20Token.constructor", call.Method)
+	require.Len(t, call.Args, 5)
+	require.Equal(t, "ABC", call.Args[1].Value)
+}
@@ -0,0 +1,226 @@
+package command
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/binance-chain/token-bind-tool/config"
+	constValue "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+// verifyStatusInterval is how long to wait between two checks of a submitted verification.
+const verifyStatusInterval = 5 * time.Second
+
+// verificationBuild reads the build of the contract to verify: the compilation of the source or the artifact of the
+// contract in --config-path, or the artifact in --artifact, or the compilation in --compilation.
+func verificationBuild() (*utils.VerificationBundle, error) {
+	configPath := viper.GetString(constValue.ConfigPath)
+	artifactPath := viper.GetString(constValue.Artifact)
+	compilationPath := viper.GetString(constValue.Compilation)
+	count := 0
+	for _, path := range []string{configPath, artifactPath, compilationPath} {
+		if len(path) != 0 {
+			count++
+		}
+	}
+	if count != 1 {
+		return nil, fmt.Errorf("expect exactly one of --%s, --%s and --%s", constValue.ConfigPath, constValue.Artifact, constValue.Compilation)
+	}
+	if len(configPath) != 0 {
+		deployConfig, err := config.LoadDeployConfig(configPath)
+		if err != nil {
+			return nil, err
+		}
+		contract, err := deployConfig.Contract(viper.GetString(constValue.Contract))
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case contract.Source != nil:
+			compilationPath = contract.CompilationPath(filepath.Dir(configPath))
+		case len(contract.Artifact) != 0:
+			artifactPath = contract.ArtifactPath(filepath.Dir(configPath))
+		default:
+			return nil, fmt.Errorf("the contract has neither source nor artifact to verify")
+		}
+	}
+	if len(compilationPath) != 0 {
+		data, err := ioutil.ReadFile(compilationPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the compilation, which is written when the source is compiled: %s", err.Error())
+		}
+		var compilation utils.Compilation
+		if err := json.Unmarshal(data, &compilation); err != nil {
+			return nil, fmt.Errorf("invalid compilation %s: %s", compilationPath, err.Error())
+		}
+		return utils.BundleFromCompilation(&compilation), nil
+	}
+	artifact, err := utils.ReadArtifact(artifactPath)
+	if err != nil {
+		return nil, err
+	}
+	// Artifacts are in out/Token.sol/Token.json or build/contracts/Token.json of the project.
+	sourceRoot := viper.GetString(constValue.SourceRoot)
+	if len(sourceRoot) == 0 {
+		sourceRoot = filepath.Dir(filepath.Dir(filepath.Dir(artifactPath)))
+	}
+	return utils.BundleFromArtifact(artifactPath, artifact, sourceRoot)
+}
+
+// fillVerificationDeployment sets the contract address and the constructor arguments of bundle. The deployment record
+// in --deployment is enough when its init code is the byte code of the build followed by the recorded arguments,
+// otherwise the arguments are taken from the deploy transaction, the one of the record or the one in --tx-hash.
+func fillVerificationDeployment(bundle *utils.VerificationBundle) error {
+	name := strings.TrimPrefix(viper.GetString(constValue.Deployment), "@")
+	txHashStr := viper.GetString(constValue.TxHash)
+	if (len(name) == 0) == (len(txHashStr) == 0) {
+		return fmt.Errorf("expect exactly one of --%s and --%s", constValue.Deployment, constValue.TxHash)
+	}
+	var txHash common.Hash
+	if len(name) != 0 {
+		deployment, err := utils.LoadDeployment(viper.GetString(constValue.DeploymentsDir), viper.GetString(constValue.NetworkType), name)
+		if err != nil {
+			return err
+		}
+		bundle.ContractAddress = deployment.Address
+		byteCode, err := hex.DecodeString(bundle.ByteCode)
+		if err == nil && crypto.Keccak256Hash(byteCode, deployment.EncodedConstructorArgs) == deployment.InitCodeHash {
+			bundle.ConstructorArgs = hex.EncodeToString(deployment.EncodedConstructorArgs)
+			return nil
+		}
+		txHash = deployment.TxHash
+	} else {
+		txHashBytes, err := hexutil.Decode(txHashStr)
+		if err != nil || len(txHashBytes) != common.HashLength {
+			return fmt.Errorf("--%s: invalid transaction hash %s", constValue.TxHash, txHashStr)
+		}
+		txHash = common.BytesToHash(txHashBytes)
+	}
+
+	ethClient, _, err := getEnv()
+	if err != nil {
+		return err
+	}
+	tx, _, err := ethClient.TransactionByHash(context.Background(), txHash)
+	if err != nil {
+		return fmt.Errorf("failed to get the deploy transaction %s: %s", txHash.String(), err.Error())
+	}
+	initCode := tx.Data()
+	if tx.To() != nil {
+		// A CREATE2 deployment calls the factory with the salt followed by the init code.
+		if len(initCode) < common.HashLength {
+			return fmt.Errorf("%s is not a contract deployment", txHash.String())
+		}
+		var salt [32]byte
+		copy(salt[:], initCode)
+		initCode = initCode[common.HashLength:]
+		if len(name) == 0 {
+			bundle.ContractAddress = utils.PredictCreate2Address(*tx.To(), salt, initCode)
+		}
+	} else if len(name) == 0 {
+		receipt, err := ethClient.TransactionReceipt(context.Background(), txHash)
+		if err != nil {
+			return err
+		}
+		bundle.ContractAddress = receipt.ContractAddress
+	}
+	bundle.ConstructorArgs, err = utils.ConstructorArgsFromInitCode(initCode, bundle.ByteCode)
+	return err
+}
+
+// submitVerification submits bundle to the verify API in --verify-url, default to bscscan, and waits for the result.
+func submitVerification(bundle *utils.VerificationBundle) error {
+	apiUrl := viper.GetString(constValue.VerifyUrl)
+	if len(apiUrl) == 0 {
+		switch viper.GetString(constValue.NetworkType) {
+		case constValue.Mainnet:
+			apiUrl = constValue.BscscanMainnetApiUrl
+		case constValue.TestNet:
+			apiUrl = constValue.BscscanTestnetApiUrl
+		default:
+			return fmt.Errorf("--%s is required on %s", constValue.VerifyUrl, viper.GetString(constValue.NetworkType))
+		}
+	}
+	apiKey := viper.GetString(constValue.ApiKey)
+	if len(apiKey) == 0 {
+		apiKey = os.Getenv(constValue.BscscanApiKeyEnv)
+	}
+	client := utils.NewEtherscanClient(apiUrl, apiKey)
+	guid, err := client.SubmitVerification(bundle)
+	if err != nil {
+		return err
+	}
+	fmt.Println(fmt.Sprintf("Submitted the verification of %s to %s, guid %s", bundle.ContractAddress.String(), apiUrl, guid))
+	for retry := 0; ; retry++ {
+		time.Sleep(verifyStatusInterval)
+		status, pending, err := client.CheckVerification(guid)
+		if err != nil {
+			return err
+		}
+		if !pending {
+			fmt.Println(status)
+			return nil
+		}
+		if retry >= constValue.VerifyStatusRetry {
+			return fmt.Errorf("the verification %s is still pending: %s", guid, status)
+		}
+	}
+}
+
+func VerificationBundleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verificationBundle --deployment {name} --config-path {config path}",
+		Short: "Generate the inputs to verify a deployed contract on a block explorer, and optionally submit them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bundle, err := verificationBuild()
+			if err != nil {
+				return err
+			}
+			if err := fillVerificationDeployment(bundle); err != nil {
+				return err
+			}
+			data, err := json.MarshalIndent(bundle, "", "  ")
+			if err != nil {
+				return err
+			}
+			if out := viper.GetString(constValue.Out); len(out) != 0 {
+				if err := ioutil.WriteFile(out, data, 0644); err != nil {
+					return fmt.Errorf("failed to write the verification bundle: %s", err.Error())
+				}
+				fmt.Println(fmt.Sprintf("Verification bundle of %s written to %s", bundle.ContractName, out))
+			} else if !viper.GetBool(constValue.Submit) {
+				fmt.Println(string(data))
+			}
+			if viper.GetBool(constValue.Submit) {
+				return submitVerification(bundle)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String(constValue.ConfigPath, "", "config file path, verify its contract compiled from source or deployed from an artifact")
+	cmd.Flags().String(constValue.Contract, "", "name of the contract, when the config has several")
+	cmd.Flags().String(constValue.Artifact, "", "Foundry, Hardhat or Truffle artifact of the contract, instead of --config-path")
+	cmd.Flags().String(constValue.Compilation, "", "compilation written when the source was compiled, instead of --config-path")
+	cmd.Flags().String(constValue.SourceRoot, "", "project directory the sources in the artifact metadata are read from, default to the directory above out/ or build/")
+	cmd.Flags().String(constValue.Deployment, "", "name of the deployment record of the contract")
+	cmd.Flags().String(constValue.TxHash, "", "deploy transaction of the contract, instead of --deployment")
+	cmd.Flags().String(constValue.Out, "", "write the bundle to this file instead of stdout")
+	cmd.Flags().Bool(constValue.Submit, false, "submit the bundle to an Etherscan compatible verify api")
+	cmd.Flags().String(constValue.VerifyUrl, "", "verify api url, default to the bscscan api of the selected network")
+	cmd.Flags().String(constValue.ApiKey, "", "verify api key, default to $"+constValue.BscscanApiKeyEnv)
+	return cmd
+}
@@ -1,42 +1,487 @@
 package config
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"gopkg.in/yaml.v2"
+
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+const (
+	// Version is the current version of the deploy config schema. A config without version is the single contract
+	// format of version 0, with the contract fields at the top level.
+	Version = 1
+	// DefaultOptimizerRuns is the optimizer runs used when the optimizer is enabled without runs.
+	DefaultOptimizerRuns = 200
+	// GasPriceUnit is the unit of a gas price without unit.
+	GasPriceUnit = "gwei"
 )
 
+// DeployConfig is a deploy config file: the target network, gas settings and the contracts to deploy, in order.
+type DeployConfig struct {
+	Version   int        `json:"version"`
+	Network   string     `json:"network,omitempty"`
+	Gas       *GasConfig `json:"gas,omitempty"`
+	Contracts []*Config  `json:"contracts"`
+}
+
+// GasConfig overrides the gas limit and gas price of the deployments.
+type GasConfig struct {
+	GasLimit uint64 `json:"gas_limit,omitempty"`
+	// GasPrice is counted in gwei, like "5" or "5 gwei", or in wei, like "5000000000 wei".
+	GasPrice Amount `json:"gas_price,omitempty"`
+}
+
+// Amount is an amount written as a JSON string, like "1.5M ABC", or as a plain JSON number.
+type Amount string
+
+func (amount *Amount) UnmarshalJSON(data []byte) error {
+	if len(data) != 0 && data[0] == '"' {
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		*amount = Amount(value)
+		return nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("expect an amount string or number: %s", err.Error())
+	}
+	*amount = Amount(number)
+	return nil
+}
+
+// Config is one contract to deploy.
 type Config struct {
-	ContractData    string `json:"contract_data"`
+	// Name identifies the contract in a config with several contracts.
+	Name         string        `json:"name,omitempty"`
+	ContractData string        `json:"contract_data"`
+	Source       *SourceConfig `json:"source,omitempty"`
+	// Artifact is the path of a Foundry, Hardhat or Truffle artifact to take the byte code and the ABI from.
+	Artifact string `json:"artifact,omitempty"`
+	// Link maps the libraries the artifact links, by name or by "path/Lib.sol:Lib", to their addresses.
+	Link map[string]string `json:"link,omitempty"`
+	// ABI is the contract ABI, inline or the path of an ABI file. It defaults to the ABI of the compiled source or
+	// of the artifact.
+	ABI json.RawMessage `json:"abi,omitempty"`
+	// ConstructorArgs are the constructor arguments as typed JSON values, packed and appended to the byte code.
+	ConstructorArgs []json.RawMessage `json:"constructor_args,omitempty"`
+	BEP2Symbol      string            `json:"bep2_symbol,omitempty"`
+	// PeggyAmount is in whole BEP2 tokens, or in BEP2 units with a "wei" suffix.
+	PeggyAmount Amount `json:"peggy_amount,omitempty"`
+	// Owner receives the total supply and the ownership of the contract.
+	Owner string `json:"owner,omitempty"`
+	// Salt deploys the contract with CREATE2 through the deterministic deployment proxy, see utils.ParseSalt.
+	Salt string `json:"salt,omitempty"`
+
+	// Compilation is the compiler output when the contract is compiled from Source.
+	Compilation *utils.Compilation `json:"-"`
+	// BuildArtifact is the artifact read from Artifact, with its file hash.
+	BuildArtifact *utils.Artifact `json:"-"`
+	// ContractABI is the resolved ABI JSON, empty without abi, source and artifact.
+	ContractABI string `json:"-"`
+	// EncodedConstructorArgs is the hex of the packed ConstructorArgs, already appended to ContractData.
+	EncodedConstructorArgs string `json:"-"`
 }
 
-func (bindConfig *Config) validate() error {
-	_, err := hex.DecodeString(bindConfig.ContractData)
+// SourceConfig is a Solidity source compiled with a local solc or solc-js instead of a pre-built contract_data.
+// Relative paths resolve against the directory of the config file.
+type SourceConfig struct {
+	Path            string `json:"path"`
+	ContractName    string `json:"contract_name"`
+	CompilerVersion string `json:"compiler_version"`
+	Optimizer       bool   `json:"optimizer"`
+	OptimizerRuns   int    `json:"optimizer_runs"`
+	EVMVersion      string `json:"evm_version"`
+	// Compiler is the path of solc or solcjs, looked up in PATH when empty.
+	Compiler string `json:"compiler"`
+	// MetadataPath receives the compiler settings, metadata and standard JSON input for verification, default to
+	// {contract_name}.compilation.json next to the source.
+	MetadataPath string `json:"metadata_path"`
+}
+
+// compile compiles Source into ContractData and writes the compilation next to it for verification.
+func (bindConfig *Config) compile(configDir string) error {
+	source := bindConfig.Source
+	sourcePath := resolvePath(configDir, source.Path)
+	runs := source.OptimizerRuns
+	if source.Optimizer && runs == 0 {
+		runs = DefaultOptimizerRuns
+	}
+	compilation, err := utils.CompileSolidity(utils.SolcSettings{
+		Compiler:        source.Compiler,
+		SourcePath:      sourcePath,
+		ContractName:    source.ContractName,
+		CompilerVersion: source.CompilerVersion,
+		Optimizer:       source.Optimizer,
+		OptimizerRuns:   runs,
+		EVMVersion:      source.EVMVersion,
+	})
+	if err != nil {
+		return err
+	}
+	metadataPath := bindConfig.CompilationPath(configDir)
+	data, err := json.MarshalIndent(compilation, "", "  ")
 	if err != nil {
-		return fmt.Errorf("invalid contract byte code: %s", err.Error())
+		return err
+	}
+	if err := ioutil.WriteFile(metadataPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write compiler metadata: %s", err.Error())
 	}
+	bindConfig.ContractData = compilation.ByteCode
+	bindConfig.Compilation = compilation
 	return nil
 }
 
-func ReadConfigData(configPath string) (Config, error) {
-	file, err := os.Open(configPath)
+// CompilationPath is where the compilation of Source is written, for verification.
+func (bindConfig *Config) CompilationPath(configDir string) string {
+	source := bindConfig.Source
+	if len(source.MetadataPath) != 0 {
+		return resolvePath(configDir, source.MetadataPath)
+	}
+	return filepath.Join(filepath.Dir(resolvePath(configDir, source.Path)), source.ContractName+".compilation.json")
+}
+
+// ArtifactPath is the path of Artifact.
+func (bindConfig *Config) ArtifactPath(configDir string) string {
+	return resolvePath(configDir, bindConfig.Artifact)
+}
+
+// linkArtifact reads Artifact and links its libraries into the byte code.
+func (bindConfig *Config) linkArtifact(configDir string) (*utils.Artifact, string, error) {
+	artifact, err := utils.ReadArtifact(bindConfig.ArtifactPath(configDir))
 	if err != nil {
-		return Config{}, fmt.Errorf("failed to open config file: %s", err.Error())
+		return nil, "", err
 	}
-	fileData, err := ioutil.ReadAll(file)
+	links := make([]string, 0, len(bindConfig.Link))
+	for name, addr := range bindConfig.Link {
+		links = append(links, name+"="+addr)
+	}
+	libraries, err := utils.ParseLibraries(links)
 	if err != nil {
-		return Config{}, err
+		return nil, "", err
+	}
+	byteCode, err := artifact.Link(libraries)
+	if err != nil {
+		return nil, "", err
+	}
+	return artifact, byteCode, nil
+}
+
+// readABI returns ABI, inline as a JSON array or a string holding one, or read from a file, and an empty string
+// without ABI.
+func (bindConfig *Config) readABI(configDir string) (string, error) {
+	abiJSON := strings.TrimSpace(string(bindConfig.ABI))
+	if !strings.HasPrefix(abiJSON, "\"") {
+		return abiJSON, nil
+	}
+	var abiPath string
+	if err := json.Unmarshal(bindConfig.ABI, &abiPath); err != nil {
+		return "", fmt.Errorf("invalid abi: %s", err.Error())
+	}
+	if strings.HasPrefix(strings.TrimSpace(abiPath), "[") {
+		return abiPath, nil
+	}
+	data, err := ioutil.ReadFile(resolvePath(configDir, abiPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to read abi file: %s", err.Error())
+	}
+	return string(data), nil
+}
+
+// resolveABI reads ABI, inline or from a file, or takes the ABI of the compilation or of the artifact.
+func (bindConfig *Config) resolveABI(configDir string) error {
+	abiJSON, err := bindConfig.readABI(configDir)
+	if err != nil {
+		return err
+	}
+	if len(abiJSON) == 0 && bindConfig.Compilation != nil {
+		abiJSON = string(bindConfig.Compilation.ABI)
+	}
+	if len(abiJSON) == 0 && bindConfig.BuildArtifact != nil {
+		abiJSON = string(bindConfig.BuildArtifact.ABI)
+	}
+	if len(abiJSON) == 0 {
+		return nil
+	}
+	if _, err := abi.JSON(strings.NewReader(abiJSON)); err != nil {
+		return fmt.Errorf("invalid abi: %s", err.Error())
+	}
+	bindConfig.ContractABI = abiJSON
+	return nil
+}
+
+// packConstructorArgs appends the packed ConstructorArgs to ContractData.
+func (bindConfig *Config) packConstructorArgs() error {
+	if len(bindConfig.ContractABI) == 0 {
+		if len(bindConfig.ConstructorArgs) != 0 {
+			return fmt.Errorf("constructor_args needs abi, source or artifact")
+		}
+		return nil
+	}
+	contractABI, err := abi.JSON(strings.NewReader(bindConfig.ContractABI))
+	if err != nil {
+		return err
+	}
+	data, err := utils.PackConstructorArgs(contractABI, bindConfig.ConstructorArgs)
+	if err != nil {
+		return err
+	}
+	bindConfig.EncodedConstructorArgs = hex.EncodeToString(data)
+	bindConfig.ContractData += bindConfig.EncodedConstructorArgs
+	return nil
+}
+
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// prepare compiles the source or links the artifact, resolves the ABI and appends the constructor arguments to
+// ContractData.
+func (bindConfig *Config) prepare(configDir string) error {
+	if bindConfig.Source != nil {
+		if err := bindConfig.compile(configDir); err != nil {
+			return err
+		}
+	}
+	if len(bindConfig.Artifact) != 0 {
+		artifact, byteCode, err := bindConfig.linkArtifact(configDir)
+		if err != nil {
+			return err
+		}
+		bindConfig.ContractData = byteCode
+		bindConfig.BuildArtifact = artifact
+	}
+	if err := bindConfig.resolveABI(configDir); err != nil {
+		return err
+	}
+	return bindConfig.packConstructorArgs()
+}
+
+// GasPrice returns the configured gas price in wei, or nil to use the default.
+func (deployConfig *DeployConfig) GasPrice() (*big.Int, error) {
+	if deployConfig.Gas == nil || len(deployConfig.Gas.GasPrice) == 0 {
+		return nil, nil
+	}
+	amount, err := utils.ParseAmount(string(deployConfig.Gas.GasPrice), 9, GasPriceUnit)
+	if err != nil {
+		return nil, err
+	}
+	return amount.Raw, nil
+}
+
+// GasLimit returns the configured gas limit, or 0 to use the default.
+func (deployConfig *DeployConfig) GasLimit() uint64 {
+	if deployConfig.Gas == nil {
+		return 0
+	}
+	return deployConfig.Gas.GasLimit
+}
+
+// Contract returns the contract called name, or the only contract when name is empty.
+func (deployConfig *DeployConfig) Contract(name string) (*Config, error) {
+	if len(name) == 0 {
+		if len(deployConfig.Contracts) != 1 {
+			return nil, fmt.Errorf("the config has %d contracts, select one by name", len(deployConfig.Contracts))
+		}
+		return deployConfig.Contracts[0], nil
+	}
+	for _, contract := range deployConfig.Contracts {
+		if contract.Name == name {
+			return contract, nil
+		}
+	}
+	return nil, fmt.Errorf("no contract %s in the config", name)
+}
+
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// substituteEnv replaces ${NAME} with the environment variable NAME, which must be set, in the string values of the
+// JSON config data. Placeholders in keys, and in YAML comments which are gone by then, are left alone, and the values
+// are never parsed again, so a value with quotes or a colon stays a single string.
+func substituteEnv(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid config: %s", err.Error())
+	}
+	var missing []string
+	value = substituteEnvValue(value, &missing)
+	if len(missing) != 0 {
+		return nil, fmt.Errorf("undefined environment variables: %s", strings.Join(missing, ", "))
+	}
+	return json.Marshal(value)
+}
+
+func substituteEnvValue(value interface{}, missing *[]string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = substituteEnvValue(item, missing)
+		}
+	case []interface{}:
+		for idx, item := range v {
+			v[idx] = substituteEnvValue(item, missing)
+		}
+	case string:
+		return envPattern.ReplaceAllStringFunc(v, func(match string) string {
+			name := envPattern.FindStringSubmatch(match)[1]
+			envValue, ok := os.LookupEnv(name)
+			if !ok {
+				*missing = append(*missing, name)
+			}
+			return envValue
+		})
+	}
+	return value
+}
+
+// toJSON converts a YAML config to JSON. JSON configs are returned as they are, so that large numbers keep their
+// precision.
+func toJSON(configPath string, data []byte) ([]byte, error) {
+	ext := strings.ToLower(filepath.Ext(configPath))
+	if ext != ".yaml" && ext != ".yml" {
+		return data, nil
+	}
+	var value interface{}
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("invalid yaml: %s", err.Error())
+	}
+	value, err := yamlToJSONValue(value)
+	if err != nil {
+		return nil, err
 	}
-	var config Config
-	err = json.Unmarshal(fileData, &config)
+	return json.Marshal(value)
+}
+
+func yamlToJSONValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			keyStr, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid yaml: non string key %v", key)
+			}
+			converted, err := yamlToJSONValue(item)
+			if err != nil {
+				return nil, err
+			}
+			object[keyStr] = converted
+		}
+		return object, nil
+	case []interface{}:
+		for idx, item := range v {
+			converted, err := yamlToJSONValue(item)
+			if err != nil {
+				return nil, err
+			}
+			v[idx] = converted
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+// LoadDeployConfig reads a JSON or YAML deploy config, substitutes environment variables and validates it without
+// touching the network or the compiler. A config of version 0 becomes a config with a single contract.
+func LoadDeployConfig(configPath string) (*DeployConfig, error) {
+	fileData, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config file: %s", err.Error())
+	}
+	fileData, err = toJSON(configPath, fileData)
+	if err != nil {
+		return nil, err
+	}
+	fileData, err = substituteEnv(fileData)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(fileData, &fields); err != nil {
+		return nil, fmt.Errorf("invalid config: %s", err.Error())
+	}
+	deployConfig := &DeployConfig{}
+	if _, ok := fields["version"]; ok {
+		if err := json.Unmarshal(fileData, deployConfig); err != nil {
+			return nil, fmt.Errorf("invalid config: %s", err.Error())
+		}
+	} else {
+		contract := &Config{}
+		if err := json.Unmarshal(fileData, contract); err != nil {
+			return nil, fmt.Errorf("invalid config: %s", err.Error())
+		}
+		deployConfig.Contracts = []*Config{contract}
+	}
+	if errs := deployConfig.validate(filepath.Dir(configPath)); len(errs) != 0 {
+		return nil, errs
+	}
+	return deployConfig, nil
+}
+
+// ReadDeployConfig loads a deploy config and prepares the byte code of every contract, compiling sources with a
+// local solc.
+func ReadDeployConfig(configPath string) (*DeployConfig, error) {
+	deployConfig, err := LoadDeployConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	for idx, contract := range deployConfig.Contracts {
+		if err := contract.prepare(filepath.Dir(configPath)); err != nil {
+			return nil, fmt.Errorf("%s: %s", contractPath(idx, deployConfig), err.Error())
+		}
+	}
+	return deployConfig, nil
+}
+
+// ReadArtifactConfig builds a deploy config with the single contract in the artifact at artifactPath, linked with the
+// libraries in links like "Lib=0x...", and prepares it.
+func ReadArtifactConfig(artifactPath string, links []string) (*DeployConfig, error) {
+	libraries, err := utils.ParseLibraries(links)
+	if err != nil {
+		return nil, err
+	}
+	contract := &Config{Artifact: artifactPath, Link: make(map[string]string, len(libraries))}
+	for name, addr := range libraries {
+		contract.Link[name] = addr.String()
+	}
+	deployConfig := &DeployConfig{Contracts: []*Config{contract}}
+	if errs := deployConfig.validate(""); len(errs) != 0 {
+		return nil, errs
+	}
+	if err := contract.prepare(""); err != nil {
+		return nil, err
+	}
+	return deployConfig, nil
+}
+
+// ReadConfigData reads a deploy config with a single contract.
+func ReadConfigData(configPath string) (Config, error) {
+	deployConfig, err := ReadDeployConfig(configPath)
 	if err != nil {
 		return Config{}, err
 	}
-	err = config.validate()
+	contract, err := deployConfig.Contract("")
 	if err != nil {
 		return Config{}, err
 	}
-	return config, nil
+	return *contract, nil
 }
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadDeployConfigVersion0(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeConfig(t, dir, "contract.json", `{"contract_data": "6080604052"}`)
+	contract, err := ReadConfigData(path)
+	require.NoError(t, err)
+	require.Equal(t, "6080604052", contract.ContractData)
+
+	path = writeConfig(t, dir, "invalid.json", `{"contract_data": "not hex"}`)
+	_, err = LoadDeployConfig(path)
+	require.Error(t, err)
+	require.Equal(t, "contract_data", err.(ValidationErrors)[0].Path)
+}
+
+func TestLoadDeployConfigYAML(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.Setenv("TEST_BEP20_OWNER", "0xaa25Aa7a19f9c426E07dee59b12f944f4d9f1DD3"))
+	defer os.Unsetenv("TEST_BEP20_OWNER")
+
+	path := writeConfig(t, dir, "deploy.yaml", `
+version: 1
+network: testnet
+gas:
+  gas_limit: 3000000
+  gas_price: 5
+contracts:
+  - name: token
+    contract_data: "6080604052"
+    abi: '[{"type":"constructor","inputs":[{"name":"supply","type":"uint256"}]}]'
+    constructor_args: ["1000000000000000000000000"]
+    bep2_symbol: ABC-123
+    peggy_amount: 1000 ABC
+    owner: ${TEST_BEP20_OWNER}
+  - name: vault
+    contract_data: "6080"
+`)
+	deployConfig, err := ReadDeployConfig(path)
+	require.NoError(t, err)
+	require.Len(t, deployConfig.Contracts, 2)
+	require.Equal(t, "testnet", deployConfig.Network)
+	require.Equal(t, uint64(3000000), deployConfig.GasLimit())
+	gasPrice, err := deployConfig.GasPrice()
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(5000000000), gasPrice)
+
+	token, err := deployConfig.Contract("token")
+	require.NoError(t, err)
+	require.Equal(t, "0xaa25Aa7a19f9c426E07dee59b12f944f4d9f1DD3", token.Owner)
+	require.Equal(t, "6080604052"+"00000000000000000000000000000000000000000000d3c21bcecceda1000000", token.ContractData)
+	_, err = deployConfig.Contract("")
+	require.Error(t, err)
+
+	require.NoError(t, os.Unsetenv("TEST_BEP20_OWNER"))
+	_, err = LoadDeployConfig(path)
+	require.EqualError(t, err, "undefined environment variables: TEST_BEP20_OWNER")
+}
+
+func TestLoadDeployConfigEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	// A value with a quote and a colon used to break the YAML, or inject fields, when it was substituted in the raw text.
+	require.NoError(t, os.Setenv("TEST_BEP2_SYMBOL", `ABC-123", owner: "0x00`))
+	defer os.Unsetenv("TEST_BEP2_SYMBOL")
+	require.NoError(t, os.Unsetenv("TEST_UNSET_OWNER"))
+
+	path := writeConfig(t, dir, "deploy.yaml", `
+version: 1
+contracts:
+  # owner: ${TEST_UNSET_OWNER}
+  - contract_data: "6080"
+    bep2_symbol: "${TEST_BEP2_SYMBOL}"
+`)
+	deployConfig, err := LoadDeployConfig(path)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "contracts[0].bep2_symbol")
+	require.NotContains(t, err.Error(), "TEST_UNSET_OWNER")
+
+	require.NoError(t, os.Setenv("TEST_BEP2_SYMBOL", "ABC-123"))
+	deployConfig, err = LoadDeployConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, "ABC-123", deployConfig.Contracts[0].BEP2Symbol)
+	require.Empty(t, deployConfig.Contracts[0].Owner)
+
+	path = writeConfig(t, dir, "deploy.json", `{"contract_data": "6080", "bep2_symbol": "${TEST_BEP2_SYMBOL}", "owner": ""}`)
+	require.NoError(t, os.Setenv("TEST_BEP2_SYMBOL", `AB"C`))
+	_, err = LoadDeployConfig(path)
+	require.Error(t, err)
+	require.Equal(t, "bep2_symbol", err.(ValidationErrors)[0].Path)
+}
+
+func TestValidateDeployConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeConfig(t, dir, "deploy.json", `{
+  "version": 1,
+  "network": "devnet",
+  "gas": {"gas_price": "1.5 wei"},
+  "contracts": [
+    {"name": "token", "contract_data": "6080", "source": {"path": "Token.sol", "contract_name": "Token"}},
+    {"name": "token", "abi": [{"type":"constructor","inputs":[{"name":"decimals","type":"uint8"}]}], "constructor_args": [256], "contract_data": "6080"},
+    {"contract_data": "6080", "bep2_symbol": "abc", "owner": "0x1234"}
+  ]
+}`)
+	_, err = LoadDeployConfig(path)
+	require.Error(t, err)
+	paths := make([]string, 0)
+	for _, fieldErr := range err.(ValidationErrors) {
+		paths = append(paths, fieldErr.Path)
+	}
+	require.Equal(t, []string{"network", "gas.gas_price", "contracts[0]", "contracts[1].name", "contracts[1]",
+		"contracts[2].name", "contracts[2].bep2_symbol", "contracts[2].owner"}, paths)
+	require.Contains(t, err.Error(), `contracts[1]: constructor_args[0] "decimals" (uint8): 256 out of range`)
+
+	path = writeConfig(t, dir, "future.json", `{"version": 2, "contracts": []}`)
+	_, err = LoadDeployConfig(path)
+	require.EqualError(t, err, "invalid config:\n  version: unsupported version 2, expect 1")
+}
+
+func TestArtifactConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	placeholder := "__Lib" + strings.Repeat("_", 35)
+	writeConfig(t, dir, "Token.json", `{
+  "_format": "hh-sol-artifact-1",
+  "contractName": "Token",
+  "abi": [{"type":"constructor","inputs":[{"name":"decimals","type":"uint8"}]}],
+  "bytecode": "0x60806073`+placeholder+`00",
+  "linkReferences": {}
+}`)
+	path := writeConfig(t, dir, "deploy.json", `{
+  "version": 1,
+  "contracts": [
+    {"name": "token", "artifact": "Token.json", "link": {"Lib": "0x1111111111111111111111111111111111111111"}, "constructor_args": [18]}
+  ]
+}`)
+	deployConfig, err := ReadDeployConfig(path)
+	require.NoError(t, err)
+	contract := deployConfig.Contracts[0]
+	require.Equal(t, "60806073"+strings.Repeat("11", 20)+"00"+strings.Repeat("0", 62)+"12", contract.ContractData)
+	require.Equal(t, "hardhat", contract.BuildArtifact.Format)
+	require.Len(t, contract.BuildArtifact.Hash, 64)
+	require.Contains(t, contract.ContractABI, "decimals")
+
+	_, err = ReadArtifactConfig(filepath.Join(dir, "Token.json"), nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "artifact: Token links libraries which are not given: Lib")
+
+	path = writeConfig(t, dir, "link.json", `{"contract_data": "6080", "link": {"Lib": "0x1111111111111111111111111111111111111111"}}`)
+	_, err = LoadDeployConfig(path)
+	require.EqualError(t, err, "invalid config:\n  link: needs artifact")
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+
+	bindconst "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/utils"
+)
+
+var bep2SymbolPattern = regexp.MustCompile(`^[A-Z0-9]{2,8}-[A-Z0-9]{3}M?$`)
+
+// FieldError is a validation error of the config field at Path, like "contracts[1].owner".
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+func (fieldErr *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", fieldErr.Path, fieldErr.Message)
+}
+
+// ValidationErrors are all the validation errors of a config.
+type ValidationErrors []*FieldError
+
+func (errs ValidationErrors) Error() string {
+	lines := make([]string, 0, len(errs))
+	for _, fieldErr := range errs {
+		lines = append(lines, "  "+fieldErr.Error())
+	}
+	return fmt.Sprintf("invalid config:\n%s", strings.Join(lines, "\n"))
+}
+
+func (errs *ValidationErrors) add(path, format string, a ...interface{}) {
+	*errs = append(*errs, &FieldError{Path: path, Message: fmt.Sprintf(format, a...)})
+}
+
+// contractPath is the path of the contract at idx, which is the top level in a config of version 0.
+func contractPath(idx int, deployConfig *DeployConfig) string {
+	if deployConfig.Version == 0 {
+		return "config"
+	}
+	return fmt.Sprintf("contracts[%d]", idx)
+}
+
+func fieldPath(prefix, field string) string {
+	if prefix == "config" {
+		return field
+	}
+	return prefix + "." + field
+}
+
+func (deployConfig *DeployConfig) validate(configDir string) ValidationErrors {
+	var errs ValidationErrors
+	if deployConfig.Version < 0 || deployConfig.Version > Version {
+		errs.add("version", "unsupported version %d, expect %d", deployConfig.Version, Version)
+		return errs
+	}
+	switch deployConfig.Network {
+	case "", bindconst.Mainnet, bindconst.TestNet, bindconst.Local:
+	default:
+		errs.add("network", "unknown network %q, expect %s, %s or %s", deployConfig.Network, bindconst.Mainnet, bindconst.TestNet, bindconst.Local)
+	}
+	if deployConfig.Gas != nil {
+		if deployConfig.Gas.GasLimit != 0 && deployConfig.Gas.GasLimit < 21000 {
+			errs.add("gas.gas_limit", "%d is below the 21000 gas of a transfer", deployConfig.Gas.GasLimit)
+		}
+		if _, err := deployConfig.GasPrice(); err != nil {
+			errs.add("gas.gas_price", err.Error())
+		}
+	}
+	if len(deployConfig.Contracts) == 0 {
+		errs.add("contracts", "at least one contract is required")
+	}
+	names := make(map[string]bool)
+	for idx, contract := range deployConfig.Contracts {
+		prefix := contractPath(idx, deployConfig)
+		if contract == nil {
+			errs.add(prefix, "must be an object")
+			continue
+		}
+		if len(deployConfig.Contracts) > 1 && len(contract.Name) == 0 {
+			errs.add(fieldPath(prefix, "name"), "is required with several contracts")
+		}
+		if len(contract.Name) != 0 {
+			if names[contract.Name] {
+				errs.add(fieldPath(prefix, "name"), "duplicate contract name %s", contract.Name)
+			}
+			names[contract.Name] = true
+		}
+		contract.validate(prefix, configDir, &errs)
+	}
+	return errs
+}
+
+func (bindConfig *Config) validate(prefix, configDir string, errs *ValidationErrors) {
+	var artifact *utils.Artifact
+	codeFields := 0
+	for _, set := range []bool{len(bindConfig.ContractData) != 0, bindConfig.Source != nil, len(bindConfig.Artifact) != 0} {
+		if set {
+			codeFields++
+		}
+	}
+	switch {
+	case codeFields > 1:
+		errs.add(prefix, "contract_data, source and artifact are exclusive")
+	case bindConfig.Source != nil:
+		source := bindConfig.Source
+		if len(source.Path) == 0 {
+			errs.add(fieldPath(prefix, "source.path"), "is required")
+		} else if _, err := os.Stat(resolvePath(configDir, source.Path)); err != nil {
+			errs.add(fieldPath(prefix, "source.path"), err.Error())
+		}
+		if len(source.ContractName) == 0 {
+			errs.add(fieldPath(prefix, "source.contract_name"), "is required")
+		}
+		if source.OptimizerRuns < 0 {
+			errs.add(fieldPath(prefix, "source.optimizer_runs"), "must not be negative")
+		}
+	case len(bindConfig.Artifact) != 0:
+		var err error
+		if artifact, _, err = bindConfig.linkArtifact(configDir); err != nil {
+			errs.add(fieldPath(prefix, "artifact"), err.Error())
+		}
+	case len(bindConfig.ContractData) == 0:
+		errs.add(fieldPath(prefix, "contract_data"), "one of contract_data, source and artifact is required")
+	default:
+		if _, err := hex.DecodeString(bindConfig.ContractData); err != nil {
+			errs.add(fieldPath(prefix, "contract_data"), "invalid contract byte code: %s", err.Error())
+		}
+	}
+	if len(bindConfig.Link) != 0 && len(bindConfig.Artifact) == 0 {
+		errs.add(fieldPath(prefix, "link"), "needs artifact")
+	}
+
+	abiJSON, err := bindConfig.readABI(configDir)
+	if err == nil && len(abiJSON) == 0 && artifact != nil {
+		abiJSON = string(artifact.ABI)
+	}
+	if err != nil {
+		errs.add(fieldPath(prefix, "abi"), err.Error())
+	} else if len(abiJSON) != 0 {
+		contractABI, err := abi.JSON(strings.NewReader(abiJSON))
+		if err != nil {
+			errs.add(fieldPath(prefix, "abi"), "invalid abi: %s", err.Error())
+		} else if _, err := utils.PackConstructorArgs(contractABI, bindConfig.ConstructorArgs); err != nil {
+			errs.add(prefix, err.Error())
+		}
+	} else if len(bindConfig.ConstructorArgs) != 0 && bindConfig.Source == nil && len(bindConfig.Artifact) == 0 {
+		errs.add(fieldPath(prefix, "constructor_args"), "needs abi, source or artifact")
+	}
+
+	bep2Prefix := ""
+	if len(bindConfig.BEP2Symbol) != 0 {
+		if !bep2SymbolPattern.MatchString(bindConfig.BEP2Symbol) {
+			errs.add(fieldPath(prefix, "bep2_symbol"), "invalid bep2 symbol %q, expect a symbol like ABC-123", bindConfig.BEP2Symbol)
+		}
+		bep2Prefix = strings.Split(bindConfig.BEP2Symbol, "-")[0]
+	}
+	if len(bindConfig.PeggyAmount) != 0 {
+		if _, err := utils.ParseAmount(string(bindConfig.PeggyAmount), bindconst.BcDecimals, bindConfig.BEP2Symbol, bep2Prefix); err != nil {
+			errs.add(fieldPath(prefix, "peggy_amount"), err.Error())
+		}
+	}
+	if len(bindConfig.Salt) != 0 {
+		if _, err := utils.ParseSalt(bindConfig.Salt); err != nil {
+			errs.add(fieldPath(prefix, "salt"), err.Error())
+		}
+	}
+	if len(bindConfig.Owner) != 0 {
+		if _, err := utils.ParseBSCAddr(bindConfig.Owner); err != nil {
+			errs.add(fieldPath(prefix, "owner"), err.Error())
+		}
+	}
+}
@@ -0,0 +1,5 @@
+package _const
+
+// BEP20TemplateBytesCode is the creation code of contracts/bep20template/BEP20Token.sol, without constructor
+// arguments. It is generated by script/gen_bep20_template.sh, run `make bep20-template` after changing the template.
+const BEP20TemplateBytesCode = ""
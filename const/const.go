@@ -8,8 +8,31 @@ const (
 	Passwd = "12345678"
 
 	NetworkType        = "network-type"
+	RPCUrl             = "rpc-url"
 	KeystorePath       = "keystore-path"
 	ConfigPath         = "config-path"
+	Contract           = "contract"
+	Salt               = "salt"
+	Create2Factory     = "create2-factory"
+	InitCode           = "init-code"
+	Artifact           = "artifact"
+	Link               = "link"
+	DeploymentsDir     = "deployments-dir"
+	Deployment         = "deployment"
+	TxHash             = "tx-hash"
+	Compilation        = "compilation"
+	SourceRoot         = "source-root"
+	Out                = "out"
+	Submit             = "submit"
+	VerifyUrl          = "verify-url"
+	ApiKey             = "api-key"
+	ProxyAddr          = "proxy-addr"
+	NewAdmin           = "new-admin"
+	Implementation     = "implementation"
+	CallData           = "call-data"
+	Ledger             = "ledger"
+	Amount             = "amount"
+	Reconcile          = "reconcile"
 	Operation          = "operation"
 	BEP20ContractAddr  = "bep20-contract-addr"
 	BEP20Owner         = "bep20-owner"
@@ -17,11 +40,28 @@ const (
 	Recipient          = "recipient"
 	PeggyAmount        = "peggy-amount"
 	LedgerAccountIndex = "ledger-account-index"
+	Output             = "output"
+	Simulate           = "simulate"
+	DryRun             = "dry-run"
+	Yes                = "yes"
+	ConfirmTestnet     = "confirm-testnet"
+	BcApiUrl           = "bc-api-url"
+	BcTokenFile        = "bc-token-file"
+	BcTokenCachePath   = "bc-token-cache"
+	BcTokenCacheTTL    = "bc-token-cache-ttl"
+
+	OutputText     = "text"
+	OutputTable    = "table"
+	OutputJSON     = "json"
+	OutputMarkdown = "markdown"
 
 	Mainnet = "mainnet"
 	TestNet = "testnet"
+	// Local is a local or private chain at --rpc-url.
+	Local = "local"
 
 	BindKeystore = "bind_keystore"
+	Deployments  = "deployments"
 
 	TestnetRPC     = "https://data-seed-prebsc-1-s1.binance.org:8545"
 	TestnetChainID = 97
@@ -38,10 +78,20 @@ const (
 	MainnetExplorerAddressUrl = "%s: https://bscscan.com/address/%s"
 	TestnetExplorerAddressUrl = "%s: https://testnet.bscscan.com/address/%s"
 
-	BSCAddrLength = 42
+	BcMaxSupply     = 9000000000000000000
+	BcDecimals      = 8
+	BcMinSymbolLen  = 2
+	BcMaxSymbolLen  = 8
+	BcMainnetApiUrl = "https://dex.binance.org/api/v1"
+	BcTestnetApiUrl = "https://testnet-dex.binance.org/api/v1"
+	BcTokenLimit    = 1000
+	BcApiMaxRetry   = 3
+	BcTokenCache    = "bc_token_cache.json"
 
-	BcMaxSupply        = 9000000000000000000
-	BcMainnnetTokenUrl = "https://dex.binance.org/api/v1/tokens?limit=1000"
+	BscscanMainnetApiUrl = "https://api.bscscan.com/api"
+	BscscanTestnetApiUrl = "https://api-testnet.bscscan.com/api"
+	BscscanApiKeyEnv     = "BSCSCAN_API_KEY"
+	VerifyStatusRetry    = 20
 )
 
 var (
@@ -4,9 +4,11 @@ go 1.13
 
 require (
 	github.com/ethereum/go-ethereum v1.9.13
+	github.com/mattn/go-sqlite3 v1.14.6
 	github.com/pkg/errors v0.8.1
 	github.com/shopspring/decimal v1.3.1
 	github.com/spf13/cobra v0.0.6
 	github.com/spf13/viper v1.6.2
 	github.com/stretchr/testify v1.4.0
+	gopkg.in/yaml.v2 v2.2.4
 )
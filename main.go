@@ -39,16 +39,28 @@ func main() {
 		Use:   "token-bind-tool",
 		Short: "Command line interface for deploy bep20 contract and bind with bep2 token",
 	}
-	rootCmd.PersistentFlags().String(constvalue.NetworkType, constvalue.Mainnet, "mainnet or testnet")
+	rootCmd.PersistentFlags().String(constvalue.NetworkType, constvalue.Mainnet, "mainnet, testnet or local")
+	rootCmd.PersistentFlags().String(constvalue.RPCUrl, "", "BSC rpc url, required with --network-type local, overrides the default rpc of mainnet and testnet")
+	rootCmd.PersistentFlags().String(constvalue.DeploymentsDir, constvalue.Deployments, "directory of the deployment records, {network}/{name}.json, which \"@Name\" address flags refer to")
 	rootCmd.AddCommand(
 		command.InitKeyCmd(),
 		command.DeployContractCmd(),
 		command.DeployCanonicalContractCmd(),
+		command.DeployBEP20TemplateCmd(),
 		command.ApproveBindAndTransferOwnershipCmd(),
 		command.DeployBEP20ContractTransferTotalSupplyAndOwnershipCmd(),
 		command.ApproveBindFromLedgerCmd(),
 		command.RefundRestBNBCmd(),
 		command.PreCheckCmd(),
+		command.IndexBindsCmd(),
+		command.SystemParamsCmd(),
+		command.InspectBytecodeCmd(),
+		command.ConfigCmd(),
+		command.PredictAddressCmd(),
+		command.VerificationBundleCmd(),
+		command.ProxyCmd(),
+		command.MintCmd(),
+		command.BurnCmd(),
 	)
 	// prepare and add flags
 	rootCmd.PersistentPreRunE = concatCobraCmdFuncs(bindFlagsLoadViper, rootCmd.PersistentPreRunE)
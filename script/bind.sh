@@ -61,4 +61,4 @@ echo "Sleep 30 second"
 sleep 30
 
 ./build/token-bind-tool approveBindAndTransferOwnership --bep20-contract-addr $bep20ContractAddr \
---network-type $networkType --peggy-amount $peggyAmount --bep2-symbol $bep2TokenSymbol --bep20-owner $tokenOwner
+--network-type $networkType --peggy-amount "$peggyAmount wei" --bep2-symbol $bep2TokenSymbol --bep20-owner $tokenOwner
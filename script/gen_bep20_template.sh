@@ -0,0 +1,36 @@
+#!/bin/bash
+
+# Compiles contracts/bep20template/BEP20Token.sol and writes its creation code to const/bep20TemplateBytesCode.go.
+# Requires solc 0.5.16, the version the template is verified with on BscScan.
+
+set -e
+
+cd "$(dirname "$0")/.."
+
+source=contracts/bep20template/BEP20Token.sol
+target=const/bep20TemplateBytesCode.go
+solcBin=${SOLC:-solc}
+
+version=$($solcBin --version | grep -o "0\.5\.16" || true)
+if [ "$version" != "0.5.16" ]
+then
+   echo "solc 0.5.16 is required, set SOLC to its path"
+   exit 1
+fi
+
+byteCode=$($solcBin --optimize --optimize-runs 200 --bin $source | awk '/^=+ .*:BEP20Token =+$/ {getline; getline; print}')
+if [ -z "$byteCode" ]
+then
+   echo "failed to compile $source"
+   exit 1
+fi
+
+cat > $target <<GO
+package _const
+
+// BEP20TemplateBytesCode is the creation code of contracts/bep20template/BEP20Token.sol, without constructor
+// arguments. It is generated by script/gen_bep20_template.sh, run \`make bep20-template\` after changing the template.
+const BEP20TemplateBytesCode = "$byteCode"
+GO
+
+echo "wrote $target"
@@ -20,7 +20,51 @@ type SendTxArgs struct {
 }
 
 type Bep2 struct {
-	Symbol          string  `json:"symbol"`
-	ContractAddress *string `json:"contract_address"`
-	TotalSupply     string  `json:"total_supply"`
+	Name             string  `json:"name"`
+	Symbol           string  `json:"symbol"`
+	OriginalSymbol   string  `json:"original_symbol"`
+	Owner            string  `json:"owner"`
+	Mintable         bool    `json:"mintable"`
+	ContractAddress  *string `json:"contract_address"`
+	ContractDecimals *int64  `json:"contract_decimals"`
+	TotalSupply      string  `json:"total_supply"`
+}
+
+// BindRecord is one bindSuccess or bindFailure event emitted by TokenManager, enriched with BEP20 metadata.
+type BindRecord struct {
+	BlockNumber  uint64 `json:"block_number"`
+	BlockTime    uint64 `json:"block_time"`
+	TxHash       string `json:"tx_hash"`
+	LogIndex     uint   `json:"log_index"`
+	Event        string `json:"event"`
+	ContractAddr string `json:"contract_addr"`
+	Bep2Symbol   string `json:"bep2_symbol"`
+	TotalSupply  string `json:"total_supply,omitempty"`
+	PeggyAmount  string `json:"peggy_amount,omitempty"`
+	FailedReason uint32 `json:"failed_reason,omitempty"`
+	Name         string `json:"name"`
+	Symbol       string `json:"symbol"`
+	Decimals     int64  `json:"decimals"`
+}
+
+const (
+	SeverityBlocking = "blocking"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// Finding is the result of a single preCheck check. A failed check with SeverityBlocking means the bind cannot succeed.
+type Finding struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Severity   string `json:"severity"`
+	Passed     bool   `json:"passed"`
+	Message    string `json:"message,omitempty"`
+	Observed   string `json:"observed,omitempty"`
+	Expected   string `json:"expected,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+func (finding *Finding) Blocking() bool {
+	return !finding.Passed && finding.Severity == SeverityBlocking
 }
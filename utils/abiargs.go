@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// PackConstructorArgs packs args, one JSON value per constructor input of contractABI, with abi.Pack(""). Integers are
+// JSON numbers or decimal or 0x hex strings, addresses, bytes and fixed bytes are hex strings, arrays are JSON arrays
+// and tuples are JSON arrays or objects keyed by component name.
+func PackConstructorArgs(contractABI abi.ABI, args []json.RawMessage) ([]byte, error) {
+	inputs := contractABI.Constructor.Inputs
+	if len(args) != len(inputs) {
+		return nil, fmt.Errorf("constructor expects %d arguments (%s), got %d", len(inputs), argumentsSignature(inputs), len(args))
+	}
+	values := make([]interface{}, 0, len(args))
+	for idx, input := range inputs {
+		name := fmt.Sprintf("constructor_args[%d]", idx)
+		if len(input.Name) != 0 {
+			name = fmt.Sprintf("%s %q", name, input.Name)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(args[idx]))
+		decoder.UseNumber()
+		var arg interface{}
+		if err := decoder.Decode(&arg); err != nil {
+			return nil, fmt.Errorf("%s (%s): invalid JSON: %s", name, input.Type.String(), err.Error())
+		}
+		value, err := abiValue(input.Type, arg, name)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value.Interface())
+	}
+	return contractABI.Pack("", values...)
+}
+
+func argumentsSignature(inputs abi.Arguments) string {
+	types := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		types = append(types, strings.TrimSpace(input.Type.String()+" "+input.Name))
+	}
+	return strings.Join(types, ", ")
+}
+
+// abiValue converts a decoded JSON value into the Go value go-ethereum packs for typ. name is the path of the value
+// in the config, used in errors.
+func abiValue(typ abi.Type, arg interface{}, name string) (reflect.Value, error) {
+	fail := func(format string, a ...interface{}) (reflect.Value, error) {
+		return reflect.Value{}, fmt.Errorf("%s (%s): %s", name, typ.String(), fmt.Sprintf(format, a...))
+	}
+	switch typ.T {
+	case abi.IntTy, abi.UintTy:
+		var text string
+		switch v := arg.(type) {
+		case json.Number:
+			text = v.String()
+		case string:
+			text = v
+		default:
+			return fail("expect a JSON number or a numeric string")
+		}
+		number, ok := new(big.Int).SetString(text, 0)
+		if !ok {
+			return fail("invalid integer %q", text)
+		}
+		if typ.T == abi.UintTy && (number.Sign() < 0 || number.BitLen() > typ.Size) {
+			return fail("%s out of range", text)
+		}
+		if typ.T == abi.IntTy {
+			limit := new(big.Int).Lsh(big.NewInt(1), uint(typ.Size-1))
+			if number.Cmp(limit) >= 0 || number.Cmp(new(big.Int).Neg(limit)) < 0 {
+				return fail("%s out of range", text)
+			}
+		}
+		if typ.Type == reflect.TypeOf(&big.Int{}) {
+			return reflect.ValueOf(number), nil
+		}
+		value := reflect.New(typ.Type).Elem()
+		if typ.T == abi.UintTy {
+			value.SetUint(number.Uint64())
+		} else {
+			value.SetInt(number.Int64())
+		}
+		return value, nil
+	case abi.BoolTy:
+		v, ok := arg.(bool)
+		if !ok {
+			return fail("expect true or false")
+		}
+		return reflect.ValueOf(v), nil
+	case abi.StringTy:
+		v, ok := arg.(string)
+		if !ok {
+			return fail("expect a JSON string")
+		}
+		return reflect.ValueOf(v), nil
+	case abi.AddressTy:
+		v, ok := arg.(string)
+		if !ok || !common.IsHexAddress(v) {
+			return fail("expect a hex address")
+		}
+		return reflect.ValueOf(common.HexToAddress(v)), nil
+	case abi.BytesTy, abi.FixedBytesTy:
+		v, ok := arg.(string)
+		if !ok {
+			return fail("expect a 0x prefixed hex string")
+		}
+		data, err := hexutil.Decode(v)
+		if err != nil {
+			return fail("invalid hex %q: %s", v, err.Error())
+		}
+		if typ.T == abi.BytesTy {
+			return reflect.ValueOf(data), nil
+		}
+		if len(data) != typ.Size {
+			return fail("expect %d bytes, got %d", typ.Size, len(data))
+		}
+		value := reflect.New(typ.Type).Elem()
+		reflect.Copy(value, reflect.ValueOf(data))
+		return value, nil
+	case abi.SliceTy, abi.ArrayTy:
+		items, ok := arg.([]interface{})
+		if !ok {
+			return fail("expect a JSON array")
+		}
+		var value reflect.Value
+		if typ.T == abi.SliceTy {
+			value = reflect.MakeSlice(typ.Type, len(items), len(items))
+		} else {
+			if len(items) != typ.Size {
+				return fail("expect %d items, got %d", typ.Size, len(items))
+			}
+			value = reflect.New(typ.Type).Elem()
+		}
+		for idx, item := range items {
+			elem, err := abiValue(*typ.Elem, item, fmt.Sprintf("%s[%d]", name, idx))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			value.Index(idx).Set(elem)
+		}
+		return value, nil
+	case abi.TupleTy:
+		var items []interface{}
+		switch v := arg.(type) {
+		case []interface{}:
+			items = v
+		case map[string]interface{}:
+			for _, field := range typ.TupleRawNames {
+				item, ok := v[field]
+				if !ok {
+					return fail("missing component %q", field)
+				}
+				items = append(items, item)
+			}
+		default:
+			return fail("expect a JSON array or object")
+		}
+		if len(items) != len(typ.TupleElems) {
+			return fail("expect %d components, got %d", len(typ.TupleElems), len(items))
+		}
+		value := reflect.New(typ.Type).Elem()
+		for idx, item := range items {
+			field, err := abiValue(*typ.TupleElems[idx], item, fmt.Sprintf("%s.%s", name, typ.TupleRawNames[idx]))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			value.Field(idx).Set(field)
+		}
+		return value, nil
+	default:
+		return fail("unsupported type")
+	}
+}
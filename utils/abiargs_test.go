@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	bindconst "github.com/binance-chain/token-bind-tool/const"
+)
+
+func rawArgs(args ...string) []json.RawMessage {
+	raw := make([]json.RawMessage, 0, len(args))
+	for _, arg := range args {
+		raw = append(raw, json.RawMessage(arg))
+	}
+	return raw
+}
+
+func TestPackConstructorArgs(t *testing.T) {
+	templateABI, err := abi.JSON(strings.NewReader(bindconst.BEP20TemplateABI))
+	require.NoError(t, err)
+
+	data, err := PackConstructorArgs(templateABI, rawArgs(`"ABC Token"`, `"ABC"`, `18`, `"1000000000000000000000000"`, `true`))
+	require.NoError(t, err)
+	expected, err := templateABI.Pack("", "ABC Token", "ABC", uint8(18), new(big.Int).Exp(big.NewInt(10), big.NewInt(24), nil), true)
+	require.NoError(t, err)
+	require.Equal(t, expected, data)
+
+	_, err = PackConstructorArgs(templateABI, rawArgs(`"ABC Token"`, `"ABC"`))
+	require.EqualError(t, err, "constructor expects 5 arguments (string name, string symbol, uint8 decimals, uint256 totalSupply, bool mintable), got 2")
+
+	_, err = PackConstructorArgs(templateABI, rawArgs(`"ABC Token"`, `"ABC"`, `256`, `"1"`, `true`))
+	require.EqualError(t, err, `constructor_args[2] "decimals" (uint8): 256 out of range`)
+
+	_, err = PackConstructorArgs(templateABI, rawArgs(`"ABC Token"`, `"ABC"`, `18`, `"1"`, `"yes"`))
+	require.EqualError(t, err, `constructor_args[4] "mintable" (bool): expect true or false`)
+}
+
+func TestPackConstructorArgsNested(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(`[{"type":"constructor","inputs":[
+{"name":"holders","type":"address[]"},
+{"name":"salt","type":"bytes32"},
+{"name":"delta","type":"int64"},
+{"name":"config","type":"tuple","components":[{"name":"admin","type":"address"},{"name":"cap","type":"uint256"}]}]}]`))
+	require.NoError(t, err)
+
+	salt := "0x" + strings.Repeat("ab", 32)
+	data, err := PackConstructorArgs(contractABI, rawArgs(
+		`["0x0000000000000000000000000000000000000001", "0x0000000000000000000000000000000000000002"]`,
+		`"`+salt+`"`, `-5`, `{"admin": "0x0000000000000000000000000000000000000003", "cap": "0x10"}`))
+	require.NoError(t, err)
+	values, err := contractABI.Constructor.Inputs.UnpackValues(data)
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}, values[0])
+	require.Equal(t, int64(-5), values[2])
+
+	_, err = PackConstructorArgs(contractABI, rawArgs(`["0x01"]`, `"`+salt+`"`, `0`, `[]`))
+	require.EqualError(t, err, `constructor_args[0] "holders"[0] (address): expect a hex address`)
+
+	_, err = PackConstructorArgs(contractABI, rawArgs(`[]`, `"0xab"`, `0`, `[]`))
+	require.EqualError(t, err, `constructor_args[1] "salt" (bytes32): expect 32 bytes, got 1`)
+
+	_, err = PackConstructorArgs(contractABI, rawArgs(`[]`, `"`+salt+`"`, `0`, `{"admin": "0x0000000000000000000000000000000000000003"}`))
+	require.EqualError(t, err, `constructor_args[3] "config" ((address,uint256)): missing component "cap"`)
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// SystemContracts are the BSC genesis system contracts by address.
+var SystemContracts = map[common.Address]string{
+	common.HexToAddress("0x0000000000000000000000000000000000001000"): "BSCValidatorSet",
+	common.HexToAddress("0x0000000000000000000000000000000000001001"): "SlashIndicator",
+	common.HexToAddress("0x0000000000000000000000000000000000001002"): "SystemReward",
+	common.HexToAddress("0x0000000000000000000000000000000000001003"): "TendermintLightClient",
+	common.HexToAddress("0x0000000000000000000000000000000000001004"): "TokenHub",
+	common.HexToAddress("0x0000000000000000000000000000000000001005"): "RelayerIncentivize",
+	common.HexToAddress("0x0000000000000000000000000000000000001006"): "RelayerHub",
+	common.HexToAddress("0x0000000000000000000000000000000000001007"): "GovHub",
+	common.HexToAddress("0x0000000000000000000000000000000000001008"): "TokenManager",
+	common.HexToAddress("0x0000000000000000000000000000000000002000"): "CrossChain",
+	common.HexToAddress("0x0000000000000000000000000000000000002001"): "Staking",
+}
+
+// ParseBSCAddr parses a hex address. It must be 0x prefixed, 40 hex chars long and not the zero address. An address
+// written in mixed case must carry a valid EIP-55 checksum, all lower or all upper case is accepted as is.
+func ParseBSCAddr(addr string) (common.Address, error) {
+	if !strings.HasPrefix(addr, "0x") || len(addr) != 2+2*common.AddressLength {
+		return common.Address{}, fmt.Errorf("invalid address %q, expect bsc address, like 0x4E656459ed25bF986Eea1196Bc1B00665401645d", addr)
+	}
+	if !common.IsHexAddress(addr) {
+		return common.Address{}, fmt.Errorf("invalid address %q, not a hex string", addr)
+	}
+	address := common.HexToAddress(addr)
+	hexPart := addr[2:]
+	if hexPart != strings.ToLower(hexPart) && hexPart != strings.ToUpper(hexPart) && address.Hex() != addr {
+		return common.Address{}, fmt.Errorf("invalid address %q, bad EIP-55 checksum, expect %s", addr, address.Hex())
+	}
+	if address == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("invalid address %q, the zero address is not allowed", addr)
+	}
+	return address, nil
+}
+
+// RequireContract returns an error if there is no code at addr.
+func RequireContract(ethClient *ethclient.Client, addr common.Address) error {
+	code, err := ethClient.CodeAt(context.Background(), addr, nil)
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("no contract code at %s", addr.String())
+	}
+	return nil
+}
+
+// CheckRecipient returns a warning when addr, which is going to receive tokens or an ownership, is a system contract
+// or a contract, and an empty string for an externally owned account.
+func CheckRecipient(ethClient *ethclient.Client, addr common.Address) (string, error) {
+	if name, ok := SystemContracts[addr]; ok {
+		return fmt.Sprintf("%s is the %s system contract", addr.String(), name), nil
+	}
+	code, err := ethClient.CodeAt(context.Background(), addr, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(code) != 0 {
+		return fmt.Sprintf("%s is a contract, make sure it can use what it receives", addr.String()), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBSCAddr(t *testing.T) {
+	addr, err := ParseBSCAddr("0x4E656459ed25bF986Eea1196Bc1B00665401645d")
+	require.NoError(t, err)
+	require.Equal(t, "0x4E656459ed25bF986Eea1196Bc1B00665401645d", addr.Hex())
+
+	_, err = ParseBSCAddr("0x4e656459ed25bf986eea1196bc1b00665401645d")
+	require.NoError(t, err)
+	_, err = ParseBSCAddr("0x4E656459ED25BF986EEA1196BC1B00665401645D")
+	require.NoError(t, err)
+
+	for _, invalid := range []string{
+		"",
+		"4E656459ed25bF986Eea1196Bc1B00665401645d",
+		"0x4E656459ed25bF986Eea1196Bc1B00665401645",
+		"0x4E656459ed25bF986Eea1196Bc1B00665401645g",
+		"0x4e656459ed25bF986Eea1196Bc1B00665401645d",
+		"0x0000000000000000000000000000000000000000",
+	} {
+		_, err := ParseBSCAddr(invalid)
+		require.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// BNBDecimals is the number of decimals of BNB on BSC.
+	BNBDecimals = 18
+	// RawUnit is the unit suffix of an amount counted in the smallest unit of a token.
+	RawUnit = "wei"
+)
+
+var amountSuffixes = map[string]int32{
+	"K": 3,
+	"M": 6,
+	"B": 9,
+	"T": 12,
+}
+
+// Amount is a token amount. Raw counts the smallest unit of a token with Decimals.
+type Amount struct {
+	Raw      *big.Int
+	Decimals int64
+	Symbol   string
+}
+
+// String renders the amount in human units followed by the raw amount, e.g. "1.5 ABC (150000000 raw, 8 decimals)".
+func (amount *Amount) String() string {
+	human := FormatUnits(amount.Raw, amount.Decimals)
+	if len(amount.Symbol) != 0 {
+		human += " " + amount.Symbol
+	}
+	return fmt.Sprintf("%s (%s raw, %d decimals)", human, amount.Raw.String(), amount.Decimals)
+}
+
+// ParseAmount parses a non-negative amount of a token with decimals. The number may have a fraction and an exponent,
+// like "1.5" or "1e6", and a K, M, B or T suffix, like "1.5M". It counts whole tokens unless it is followed by the
+// unit "wei", which counts the smallest unit. A unit other than "wei" must be one of symbols, case insensitively.
+// Amounts which do not come out as a whole number of the smallest unit are rejected rather than rounded.
+func ParseAmount(input string, decimals int64, symbols ...string) (*Amount, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("invalid amount %q, expect a number with an optional unit, like \"1.5M\", \"1000 ABC\" or \"1e18 wei\"", input)
+	}
+	symbol := ""
+	if len(symbols) != 0 {
+		symbol = symbols[0]
+	}
+	raw := false
+	if len(fields) == 2 {
+		unit := fields[1]
+		if strings.EqualFold(unit, RawUnit) {
+			raw = true
+		} else if !containsFold(symbols, unit) {
+			return nil, fmt.Errorf("invalid amount %q, unknown unit %s, expect %s", input, unit, strings.Join(append(append([]string{}, symbols...), RawUnit), " or "))
+		}
+	}
+
+	number := fields[0]
+	var shift int32
+	if exp, ok := amountSuffixes[strings.ToUpper(number[len(number)-1:])]; ok {
+		number, shift = number[:len(number)-1], exp
+	}
+	value, err := decimal.NewFromString(number)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount %q: %s", input, err.Error())
+	}
+	if value.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount %q, must not be negative", input)
+	}
+	if !raw {
+		shift += int32(decimals)
+	}
+	value = value.Shift(shift)
+	if !value.Equal(value.Truncate(0)) {
+		return nil, fmt.Errorf("invalid amount %q, more precise than the %d decimals of the token", input, decimals)
+	}
+	return &Amount{Raw: value.BigInt(), Decimals: decimals, Symbol: symbol}, nil
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if len(item) != 0 && strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatUnits renders amount, counted in the smallest unit, as a decimal number of a token with decimals.
+func FormatUnits(amount *big.Int, decimals int64) string {
+	return decimal.NewFromBigInt(amount, -int32(decimals)).String()
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAmount(t *testing.T) {
+	for input, expected := range map[string]string{
+		"1.5M":         "150000000000000",
+		"1000 ABC":     "100000000000",
+		"1000 abc-123": "100000000000",
+		"0.00000001":   "1",
+		"1e3":          "100000000000",
+		"15 wei":       "15",
+		"1.5K wei":     "1500",
+		"0":            "0",
+		"2B":           "200000000000000000",
+	} {
+		amount, err := ParseAmount(input, 8, "ABC-123", "ABC")
+		require.NoError(t, err, input)
+		require.Equal(t, expected, amount.Raw.String(), input)
+	}
+
+	for _, input := range []string{"", "abc", "-1", "0.000000001", "1.5 wei", "1000 XYZ", "1 ABC extra", "M"} {
+		_, err := ParseAmount(input, 8, "ABC-123", "ABC")
+		require.Error(t, err, input)
+	}
+
+	amount, err := ParseAmount("1e18 wei", 18, "BNB")
+	require.NoError(t, err)
+	require.Equal(t, "1 BNB (1000000000000000000 raw, 18 decimals)", amount.String())
+}
+
+func TestFormatUnits(t *testing.T) {
+	require.Equal(t, "1.5", FormatUnits(big.NewInt(150000000), 8))
+	require.Equal(t, "0", FormatUnits(big.NewInt(0), 18))
+}
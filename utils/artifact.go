@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	ArtifactFoundry = "foundry"
+	ArtifactHardhat = "hardhat"
+	ArtifactTruffle = "truffle"
+)
+
+// libraryPlaceholderPattern matches a library placeholder in hex byte code, "__$<34 hex>$__" since solc 0.5 and
+// "__<name>___" before.
+var libraryPlaceholderPattern = regexp.MustCompile(`__\$[0-9a-fA-F]{34}\$__|__[^_$][^$]{35}__`)
+
+// LinkReference is where a library address goes in the byte code, counted in bytes.
+type LinkReference struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// Artifact is the byte code and ABI of a contract built by Foundry, Hardhat or Truffle.
+type Artifact struct {
+	Format       string
+	ContractName string
+	// SourceName is the source path of the contract in a Hardhat artifact.
+	SourceName string
+	ABI        json.RawMessage
+	// Metadata is the solc metadata of a Foundry or Truffle artifact, a JSON object or a string holding one.
+	Metadata json.RawMessage
+	// ByteCode is the hex creation code without 0x, with placeholders for the libraries it links.
+	ByteCode string
+	// LinkReferences are the library positions by source and library name, empty for Truffle.
+	LinkReferences map[string]map[string][]LinkReference
+	// Hash is the sha256 of the artifact file.
+	Hash string
+}
+
+type artifactFile struct {
+	Format         string                                `json:"_format"`
+	ContractName   string                                `json:"contractName"`
+	SourceName     string                                `json:"sourceName"`
+	Metadata       json.RawMessage                       `json:"metadata"`
+	ABI            json.RawMessage                       `json:"abi"`
+	ByteCode       json.RawMessage                       `json:"bytecode"`
+	LinkReferences map[string]map[string][]LinkReference `json:"linkReferences"`
+}
+
+type foundryByteCode struct {
+	Object         string                                `json:"object"`
+	LinkReferences map[string]map[string][]LinkReference `json:"linkReferences"`
+}
+
+// ReadArtifact reads a Foundry out/*.json, Hardhat artifacts/**.json or Truffle build/contracts/*.json artifact.
+func ReadArtifact(path string) (*Artifact, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %s", err.Error())
+	}
+	var file artifactFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid artifact %s: %s", path, err.Error())
+	}
+	if len(file.ABI) == 0 || len(file.ByteCode) == 0 {
+		return nil, fmt.Errorf("invalid artifact %s, abi or bytecode is missing", path)
+	}
+	hash := sha256.Sum256(data)
+	artifact := &Artifact{
+		ContractName:   file.ContractName,
+		SourceName:     file.SourceName,
+		ABI:            file.ABI,
+		Metadata:       file.Metadata,
+		LinkReferences: file.LinkReferences,
+		Hash:           hex.EncodeToString(hash[:]),
+	}
+	var byteCode string
+	if file.ByteCode[0] == '{' {
+		var foundry foundryByteCode
+		if err := json.Unmarshal(file.ByteCode, &foundry); err != nil {
+			return nil, fmt.Errorf("invalid artifact %s: %s", path, err.Error())
+		}
+		artifact.Format = ArtifactFoundry
+		artifact.ContractName = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		artifact.LinkReferences = foundry.LinkReferences
+		byteCode = foundry.Object
+	} else {
+		if err := json.Unmarshal(file.ByteCode, &byteCode); err != nil {
+			return nil, fmt.Errorf("invalid artifact %s: %s", path, err.Error())
+		}
+		artifact.Format = ArtifactTruffle
+		if strings.HasPrefix(file.Format, "hh-sol-artifact") {
+			artifact.Format = ArtifactHardhat
+		}
+	}
+	artifact.ByteCode = strings.TrimPrefix(byteCode, "0x")
+	if len(artifact.ByteCode) == 0 {
+		return nil, fmt.Errorf("artifact %s has no byte code, the contract may be abstract or an interface", path)
+	}
+	return artifact, nil
+}
+
+// ParseLibraries parses library links like "Lib=0x...". A library is named by its name or by "path/Lib.sol:Lib".
+func ParseLibraries(links []string) (map[string]common.Address, error) {
+	libraries := make(map[string]common.Address, len(links))
+	for _, link := range links {
+		parts := strings.SplitN(link, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return nil, fmt.Errorf("invalid library link %q, expect Lib=0x...", link)
+		}
+		addr, err := ParseBSCAddr(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid library link %q: %s", link, err.Error())
+		}
+		libraries[parts[0]] = addr
+	}
+	return libraries, nil
+}
+
+// Link replaces the library placeholders in the byte code with the addresses in libraries and returns the hex byte
+// code. It fails when a placeholder is left.
+func (artifact *Artifact) Link(libraries map[string]common.Address) (string, error) {
+	code := []byte(artifact.ByteCode)
+	for source, names := range artifact.LinkReferences {
+		for name, refs := range names {
+			addr, ok := findLibrary(libraries, source, name)
+			if !ok {
+				continue
+			}
+			addrHex := hex.EncodeToString(addr.Bytes())
+			for _, ref := range refs {
+				if ref.Start < 0 || ref.Length != common.AddressLength || 2*(ref.Start+ref.Length) > len(code) {
+					return "", fmt.Errorf("invalid link reference of %s at %d", name, ref.Start)
+				}
+				copy(code[2*ref.Start:], addrHex)
+			}
+		}
+	}
+	linked := libraryPlaceholderPattern.ReplaceAllStringFunc(string(code), func(placeholder string) string {
+		for name, addr := range libraries {
+			if placeholderOf(name, placeholder) {
+				return hex.EncodeToString(addr.Bytes())
+			}
+		}
+		return placeholder
+	})
+	if unlinked := artifact.unlinkedLibraries(linked); len(unlinked) != 0 {
+		return "", fmt.Errorf("%s links libraries which are not given: %s, set --link Lib=0x...", artifact.ContractName, strings.Join(unlinked, ", "))
+	}
+	if _, err := hex.DecodeString(linked); err != nil {
+		return "", fmt.Errorf("invalid byte code in artifact: %s", err.Error())
+	}
+	return linked, nil
+}
+
+// findLibrary looks a library up by name or by source:name.
+func findLibrary(libraries map[string]common.Address, source, name string) (common.Address, bool) {
+	if addr, ok := libraries[source+":"+name]; ok {
+		return addr, true
+	}
+	addr, ok := libraries[name]
+	return addr, ok
+}
+
+// placeholderOf tells whether placeholder stands for the library name, which is "Lib" or "path/Lib.sol:Lib".
+func placeholderOf(name, placeholder string) bool {
+	if strings.HasPrefix(placeholder, "__$") {
+		return strings.Contains(name, ":") && strings.EqualFold(placeholder[3:37], hex.EncodeToString(crypto.Keccak256([]byte(name)))[:34])
+	}
+	legacy := strings.Trim(placeholder, "_")
+	return legacy == name || (strings.Contains(legacy, ":") && legacy[strings.LastIndex(legacy, ":")+1:] == name)
+}
+
+// unlinkedLibraries names the libraries with a placeholder left in code.
+func (artifact *Artifact) unlinkedLibraries(code string) []string {
+	unlinked := make(map[string]bool)
+	for source, names := range artifact.LinkReferences {
+		for name, refs := range names {
+			for _, ref := range refs {
+				end := 2 * (ref.Start + ref.Length)
+				if ref.Start < 0 || end > len(code) || strings.Contains(code[2*ref.Start:end], "__") {
+					unlinked[source+":"+name] = true
+				}
+			}
+		}
+	}
+	for _, placeholder := range libraryPlaceholderPattern.FindAllString(code, -1) {
+		if !strings.HasPrefix(placeholder, "__$") {
+			unlinked[strings.Trim(placeholder, "_")] = true
+		} else if len(artifact.LinkReferences) == 0 {
+			unlinked[placeholder] = true
+		}
+	}
+	names := make([]string, 0, len(unlinked))
+	for name := range unlinked {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
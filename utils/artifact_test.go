@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+const artifactABI = `[{"inputs":[],"stateMutability":"nonpayable","type":"constructor"}]`
+
+// libraryCode pushes the library address, or its placeholder, from byte 4 and stops.
+func libraryCode(placeholder string) string {
+	return "608060" + "73" + placeholder + "00"
+}
+
+func writeArtifact(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestReadArtifact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "artifact")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeArtifact(t, dir, "Token.json", `{"abi":`+artifactABI+`,"bytecode":{"object":"0x6080","linkReferences":{}},"deployedBytecode":{"object":"0x"}}`)
+	artifact, err := ReadArtifact(path)
+	require.NoError(t, err)
+	require.Equal(t, ArtifactFoundry, artifact.Format)
+	require.Equal(t, "Token", artifact.ContractName)
+	require.Equal(t, "6080", artifact.ByteCode)
+	require.JSONEq(t, artifactABI, string(artifact.ABI))
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	hash := sha256.Sum256(data)
+	require.Equal(t, hex.EncodeToString(hash[:]), artifact.Hash)
+
+	path = writeArtifact(t, dir, "hardhat.json", `{"_format":"hh-sol-artifact-1","contractName":"Token","sourceName":"contracts/Token.sol","abi":`+artifactABI+`,"bytecode":"0x6080","linkReferences":{}}`)
+	artifact, err = ReadArtifact(path)
+	require.NoError(t, err)
+	require.Equal(t, ArtifactHardhat, artifact.Format)
+	require.Equal(t, "Token", artifact.ContractName)
+
+	path = writeArtifact(t, dir, "truffle.json", `{"contractName":"Token","abi":`+artifactABI+`,"bytecode":"0x6080","networks":{}}`)
+	artifact, err = ReadArtifact(path)
+	require.NoError(t, err)
+	require.Equal(t, ArtifactTruffle, artifact.Format)
+
+	path = writeArtifact(t, dir, "IToken.json", `{"abi":[],"bytecode":{"object":"0x"}}`)
+	_, err = ReadArtifact(path)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no byte code")
+
+	path = writeArtifact(t, dir, "abi.json", artifactABI)
+	_, err = ReadArtifact(path)
+	require.Error(t, err)
+}
+
+func TestArtifactLink(t *testing.T) {
+	libAddr := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	linked := libraryCode(hex.EncodeToString(libAddr.Bytes()))
+
+	hash := hex.EncodeToString(crypto.Keccak256([]byte("src/Lib.sol:Lib")))[:34]
+	artifact := &Artifact{
+		ContractName: "Token",
+		ByteCode:     libraryCode("__$" + hash + "$__"),
+		LinkReferences: map[string]map[string][]LinkReference{
+			"src/Lib.sol": {"Lib": {{Start: 4, Length: 20}}},
+		},
+	}
+	_, err := artifact.Link(nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "src/Lib.sol:Lib")
+	code, err := artifact.Link(map[string]common.Address{"Lib": libAddr})
+	require.NoError(t, err)
+	require.Equal(t, linked, code)
+	code, err = artifact.Link(map[string]common.Address{"src/Lib.sol:Lib": libAddr})
+	require.NoError(t, err)
+	require.Equal(t, linked, code)
+
+	// A solc placeholder without link references only matches the fully qualified name.
+	artifact.LinkReferences = nil
+	_, err = artifact.Link(map[string]common.Address{"Lib": libAddr})
+	require.Error(t, err)
+	code, err = artifact.Link(map[string]common.Address{"src/Lib.sol:Lib": libAddr})
+	require.NoError(t, err)
+	require.Equal(t, linked, code)
+
+	truffle := &Artifact{ContractName: "Token", ByteCode: libraryCode("__Lib" + strings.Repeat("_", 35))}
+	_, err = truffle.Link(map[string]common.Address{"Other": libAddr})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Lib")
+	code, err = truffle.Link(map[string]common.Address{"Lib": libAddr})
+	require.NoError(t, err)
+	require.Equal(t, linked, code)
+}
+
+func TestParseLibraries(t *testing.T) {
+	libraries, err := ParseLibraries([]string{"Lib=0x1111111111111111111111111111111111111111"})
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0x1111111111111111111111111111111111111111"), libraries["Lib"])
+
+	_, err = ParseLibraries([]string{"Lib"})
+	require.Error(t, err)
+	_, err = ParseLibraries([]string{"Lib=0x11"})
+	require.Error(t, err)
+}
@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	bindconst "github.com/binance-chain/token-bind-tool/const"
+	bindtypes "github.com/binance-chain/token-bind-tool/types"
+)
+
+// BcTokenClient looks up BEP2 and BEP8 mini tokens: from a local token list file if TokenFile is set, otherwise
+// from the Beacon Chain api at ApiUrl. Api results, and symbols which are not found, are cached in CachePath for
+// CacheTTL. A zero CacheTTL disables the cache.
+type BcTokenClient struct {
+	ApiUrl    string
+	TokenFile string
+	CachePath string
+	CacheTTL  time.Duration
+	MaxRetry  int
+	PageLimit int
+
+	httpClient *http.Client
+}
+
+// bcTokenCacheEntry is a token as fetched from the api, or a symbol which was not found when Token is nil.
+type bcTokenCacheEntry struct {
+	Token     *bindtypes.Bep2 `json:"token"`
+	FetchedAt time.Time       `json:"fetched_at"`
+}
+
+func NewBcTokenClient(apiUrl, tokenFile, cachePath string, cacheTTL time.Duration) *BcTokenClient {
+	return &BcTokenClient{
+		ApiUrl:     strings.TrimSuffix(apiUrl, "/"),
+		TokenFile:  tokenFile,
+		CachePath:  cachePath,
+		CacheTTL:   cacheTTL,
+		MaxRetry:   bindconst.BcApiMaxRetry,
+		PageLimit:  bindconst.BcTokenLimit,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (client *BcTokenClient) String() string {
+	if len(client.TokenFile) != 0 {
+		return client.TokenFile
+	}
+	return client.ApiUrl
+}
+
+// IsMiniToken tells whether symbol is a BEP8 mini token symbol, like ABC-5CAM.
+func IsMiniToken(symbol string) bool {
+	parts := strings.Split(symbol, "-")
+	return len(parts) == 2 && len(parts[1]) == 4 && strings.HasSuffix(parts[1], "M")
+}
+
+func (client *BcTokenClient) GetBep2Token(symbol string) (*bindtypes.Bep2, error) {
+	if len(client.TokenFile) != 0 {
+		return client.getTokenFromFile(symbol)
+	}
+
+	cache := client.loadCache()
+	if entry, ok := cache[client.cacheKey(symbol)]; ok && time.Since(entry.FetchedAt) < client.CacheTTL {
+		if entry.Token == nil {
+			return nil, fmt.Errorf("cannot find the BEP2 %s in %s, cached at %s", symbol, client.String(), entry.FetchedAt.Format(time.RFC3339))
+		}
+		return entry.Token, nil
+	}
+
+	endpoint := "tokens"
+	if IsMiniToken(symbol) {
+		endpoint = "mini/tokens"
+	}
+	now := time.Now()
+	var found *bindtypes.Bep2
+	for offset := 0; found == nil; offset += client.PageLimit {
+		tokens, err := client.getTokenPage(endpoint, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, token := range tokens {
+			cache[client.cacheKey(token.Symbol)] = &bcTokenCacheEntry{Token: token, FetchedAt: now}
+			if token.Symbol == symbol {
+				found = token
+			}
+		}
+		if len(tokens) < client.PageLimit {
+			break
+		}
+	}
+	if found == nil {
+		// Remember the miss, otherwise every lookup of an unknown symbol pages through the whole token list.
+		cache[client.cacheKey(symbol)] = &bcTokenCacheEntry{FetchedAt: now}
+	}
+	client.saveCache(cache)
+	if found == nil {
+		return nil, fmt.Errorf("cannot find the BEP2 %s in %s", symbol, client.String())
+	}
+	return found, nil
+}
+
+func (client *BcTokenClient) getTokenFromFile(symbol string) (*bindtypes.Bep2, error) {
+	data, err := ioutil.ReadFile(client.TokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %s", err.Error())
+	}
+	var tokens []*bindtypes.Bep2
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		if token.Symbol == symbol {
+			return token, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot find the BEP2 %s in %s", symbol, client.String())
+}
+
+func (client *BcTokenClient) getTokenPage(endpoint string, offset int) ([]*bindtypes.Bep2, error) {
+	url := fmt.Sprintf("%s/%s?limit=%d&offset=%d", client.ApiUrl, endpoint, client.PageLimit, offset)
+	var lastErr error
+	for retry := 0; retry <= client.MaxRetry; retry++ {
+		if retry > 0 {
+			time.Sleep(time.Duration(retry) * time.Second)
+		}
+		tokens, err := client.get(url)
+		if err == nil {
+			return tokens, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("failed to query %s after %d retries: %s", url, client.MaxRetry, lastErr.Error())
+}
+
+func (client *BcTokenClient) get(url string) ([]*bindtypes.Bep2, error) {
+	resp, err := client.httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status: %s", resp.Status)
+	}
+	var tokens []*bindtypes.Bep2
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (client *BcTokenClient) cacheKey(symbol string) string {
+	return client.ApiUrl + "|" + symbol
+}
+
+// loadCache never fails, a missing or broken cache file only means every lookup goes to the api.
+func (client *BcTokenClient) loadCache() map[string]*bcTokenCacheEntry {
+	cache := make(map[string]*bcTokenCacheEntry)
+	if len(client.CachePath) == 0 || client.CacheTTL <= 0 {
+		return cache
+	}
+	data, err := ioutil.ReadFile(client.CachePath)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return make(map[string]*bcTokenCacheEntry)
+	}
+	return cache
+}
+
+func (client *BcTokenClient) saveCache(cache map[string]*bcTokenCacheEntry) {
+	if len(client.CachePath) == 0 || client.CacheTTL <= 0 {
+		return
+	}
+	for key, entry := range cache {
+		if time.Since(entry.FetchedAt) >= client.CacheTTL {
+			delete(cache, key)
+		}
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	if err := ioutil.WriteFile(client.CachePath, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write BEP2 token cache %s: %s\n", client.CachePath, err.Error())
+	}
+}
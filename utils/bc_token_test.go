@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	bindtypes "github.com/binance-chain/token-bind-tool/types"
+)
+
+const testTokenList = `[
+	{"symbol": "ABC-123", "contract_address": null, "total_supply": "1000.00000000"},
+	{"symbol": "XYZ-456", "contract_address": "0x4E656459ed25bF986Eea1196Bc1B00665401645d", "total_supply": "21.5"}
+]`
+
+func newTestBcApi(t *testing.T, tokenCount int, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		require.NoError(t, err)
+		offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+		require.NoError(t, err)
+
+		tokens := make([]*bindtypes.Bep2, 0)
+		switch r.URL.Path {
+		case "/api/v1/tokens":
+			for idx := offset; idx < offset+limit && idx < tokenCount; idx++ {
+				tokens = append(tokens, &bindtypes.Bep2{Symbol: fmt.Sprintf("T%d-000", idx), TotalSupply: "1"})
+			}
+		case "/api/v1/mini/tokens":
+			tokens = append(tokens, &bindtypes.Bep2{Symbol: "MINI-5CAM", TotalSupply: "1", Mintable: true})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(tokens))
+	}))
+}
+
+func TestBcTokenClientPagination(t *testing.T) {
+	requests := 0
+	server := newTestBcApi(t, 25, &requests)
+	defer server.Close()
+
+	client := NewBcTokenClient(server.URL+"/api/v1", "", "", 0)
+	client.PageLimit = 10
+	token, err := client.GetBep2Token("T23-000")
+	require.NoError(t, err)
+	require.Equal(t, "T23-000", token.Symbol)
+	require.Equal(t, 3, requests)
+
+	_, err = client.GetBep2Token("NOPE-000")
+	require.Error(t, err)
+}
+
+func TestBcTokenClientMiniToken(t *testing.T) {
+	require.True(t, IsMiniToken("MINI-5CAM"))
+	require.False(t, IsMiniToken("ABC-123"))
+
+	requests := 0
+	server := newTestBcApi(t, 0, &requests)
+	defer server.Close()
+
+	client := NewBcTokenClient(server.URL+"/api/v1", "", "", 0)
+	token, err := client.GetBep2Token("MINI-5CAM")
+	require.NoError(t, err)
+	require.True(t, token.Mintable)
+}
+
+func TestBcTokenClientCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bc_token")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	requests := 0
+	server := newTestBcApi(t, 5, &requests)
+	defer server.Close()
+
+	client := NewBcTokenClient(server.URL+"/api/v1", "", filepath.Join(dir, "cache.json"), time.Hour)
+	_, err = client.GetBep2Token("T1-000")
+	require.NoError(t, err)
+	_, err = client.GetBep2Token("T4-000")
+	require.NoError(t, err)
+	require.Equal(t, 1, requests)
+
+	_, err = client.GetBep2Token("NOPE-000")
+	require.Error(t, err)
+	require.Equal(t, 2, requests)
+	_, err = client.GetBep2Token("NOPE-000")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cached at")
+	require.Equal(t, 2, requests)
+
+	// Without a TTL the cache file is neither read nor written.
+	client = NewBcTokenClient(server.URL+"/api/v1", "", filepath.Join(dir, "cache.json"), 0)
+	_, err = client.GetBep2Token("T1-000")
+	require.NoError(t, err)
+	require.Equal(t, 3, requests)
+}
+
+func TestBcTokenClientFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bc_token")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "tokens.json")
+	require.NoError(t, ioutil.WriteFile(tokenFile, []byte(testTokenList), 0644))
+
+	client := NewBcTokenClient("", tokenFile, "", 0)
+	token, err := client.GetBep2Token("ABC-123")
+	require.NoError(t, err)
+	require.Nil(t, token.ContractAddress)
+	token, err = client.GetBep2Token("XYZ-456")
+	require.NoError(t, err)
+	require.Equal(t, "21.5", token.TotalSupply)
+}
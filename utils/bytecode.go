@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	// EIP1967ImplementationSlot is bytes32(uint256(keccak256('eip1967.proxy.implementation')) - 1).
+	EIP1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	// EIP1967AdminSlot is bytes32(uint256(keccak256('eip1967.proxy.admin')) - 1).
+	EIP1967AdminSlot = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+
+	// RequiredBEP20Methods must all be implemented by a BEP20 contract which is going to be bound.
+	RequiredBEP20Methods = []string{
+		"getOwner()",
+		"decimals()",
+		"symbol()",
+		"totalSupply()",
+		"approve(address,uint256)",
+		"transferOwnership(address)",
+	}
+	MintMethod = "mint(uint256)"
+	BurnMethod = "burn(uint256)"
+)
+
+// BytecodeReport is the result of the static analysis of a contract byte code.
+type BytecodeReport struct {
+	Address        string   `json:"address,omitempty"`
+	IsProxy        bool     `json:"is_proxy"`
+	Implementation string   `json:"implementation,omitempty"`
+	Selectors      []string `json:"selectors"`
+	MissingMethods []string `json:"missing_methods"`
+	HasMint        bool     `json:"has_mint"`
+	HasBurn        bool     `json:"has_burn"`
+}
+
+// MethodSelector returns the 4 bytes selector of a method signature, like "approve(address,uint256)".
+func MethodSelector(signature string) [4]byte {
+	var selector [4]byte
+	copy(selector[:], crypto.Keccak256([]byte(signature))[:4])
+	return selector
+}
+
+// ExtractSelectors walks the byte code instruction by instruction and collects every PUSH1 to PUSH4 operand, left padded
+// to 4 bytes, which is how solc dispatches on function selectors: a selector with leading zero bytes, like 0x00fdd58e,
+// is pushed with PUSH3 or smaller. Creation code can be passed as well since the runtime code is embedded in it.
+func ExtractSelectors(code []byte) map[[4]byte]bool {
+	selectors := make(map[[4]byte]bool)
+	for pc := 0; pc < len(code); pc++ {
+		op := vm.OpCode(code[pc])
+		if op < vm.PUSH1 || op > vm.PUSH32 {
+			continue
+		}
+		size := int(op-vm.PUSH1) + 1
+		if op <= vm.PUSH4 && pc+1+size <= len(code) {
+			var selector [4]byte
+			copy(selector[4-size:], code[pc+1:pc+1+size])
+			selectors[selector] = true
+		}
+		pc += size
+	}
+	return selectors
+}
+
+// containsPush32 reports whether value is pushed as a PUSH32 operand anywhere in code.
+func containsPush32(code []byte, value common.Hash) bool {
+	for pc := 0; pc < len(code); pc++ {
+		op := vm.OpCode(code[pc])
+		if op < vm.PUSH1 || op > vm.PUSH32 {
+			continue
+		}
+		size := int(op-vm.PUSH1) + 1
+		if op == vm.PUSH32 && pc+1+size <= len(code) && common.BytesToHash(code[pc+1:pc+1+size]) == value {
+			return true
+		}
+		pc += size
+	}
+	return false
+}
+
+// AnalyzeBytecode reports the BEP20 methods missing from code and whether it can mint or burn.
+func AnalyzeBytecode(code []byte) *BytecodeReport {
+	selectors := ExtractSelectors(code)
+	report := &BytecodeReport{
+		IsProxy:        containsPush32(code, EIP1967ImplementationSlot),
+		Selectors:      make([]string, 0, len(selectors)),
+		MissingMethods: make([]string, 0),
+	}
+	for selector := range selectors {
+		report.Selectors = append(report.Selectors, hexutil.Encode(selector[:]))
+	}
+	sort.Strings(report.Selectors)
+	for _, method := range RequiredBEP20Methods {
+		if !selectors[MethodSelector(method)] {
+			report.MissingMethods = append(report.MissingMethods, method)
+		}
+	}
+	report.HasMint = selectors[MethodSelector(MintMethod)]
+	report.HasBurn = selectors[MethodSelector(BurnMethod)]
+	return report
+}
+
+// InspectContract analyzes the deployed code at contractAddr. If the contract is an EIP-1967 proxy, the code of its
+// implementation is analyzed instead.
+func InspectContract(ethClient *ethclient.Client, contractAddr common.Address) (*BytecodeReport, error) {
+	code, err := ethClient.CodeAt(context.Background(), contractAddr, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("no contract code at %s", contractAddr.String())
+	}
+	slot, err := ethClient.StorageAt(context.Background(), contractAddr, EIP1967ImplementationSlot, nil)
+	if err != nil {
+		return nil, err
+	}
+	implementation := common.BytesToAddress(slot)
+	if implementation == (common.Address{}) {
+		report := AnalyzeBytecode(code)
+		report.Address = contractAddr.String()
+		return report, nil
+	}
+
+	implCode, err := ethClient.CodeAt(context.Background(), implementation, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(implCode) == 0 {
+		return nil, fmt.Errorf("proxy %s points to %s which has no code", contractAddr.String(), implementation.String())
+	}
+	report := AnalyzeBytecode(implCode)
+	report.Address = contractAddr.String()
+	report.IsProxy = true
+	report.Implementation = implementation.String()
+	return report, nil
+}
+
+// MintableMismatch returns a warning when the mint capability of the BEP20 does not follow the BEP2 mintable flag,
+// or an empty string when they agree.
+func (report *BytecodeReport) MintableMismatch(bep2Mintable bool) string {
+	if bep2Mintable && !report.HasMint {
+		return "the BEP2 token is mintable but the BEP20 contract does not implement mint"
+	}
+	if !bep2Mintable && report.HasMint {
+		return "the BEP2 token is not mintable but the BEP20 contract implements mint"
+	}
+	return ""
+}
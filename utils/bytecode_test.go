@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/require"
+)
+
+func pushSelectors(methods ...string) []byte {
+	var code []byte
+	for _, method := range methods {
+		selector := MethodSelector(method)
+		code = append(code, byte(vm.DUP1), byte(vm.PUSH4))
+		code = append(code, selector[:]...)
+		code = append(code, byte(vm.EQ))
+	}
+	return code
+}
+
+func TestAnalyzeBytecode(t *testing.T) {
+	require.Equal(t, [4]byte{0x09, 0x5e, 0xa7, 0xb3}, MethodSelector("approve(address,uint256)"))
+
+	code := pushSelectors(append(RequiredBEP20Methods, MintMethod)...)
+	report := AnalyzeBytecode(code)
+	require.Empty(t, report.MissingMethods)
+	require.True(t, report.HasMint)
+	require.False(t, report.HasBurn)
+	require.False(t, report.IsProxy)
+	require.Equal(t, "", report.MintableMismatch(true))
+	require.NotEqual(t, "", report.MintableMismatch(false))
+
+	report = AnalyzeBytecode(pushSelectors("decimals()", "symbol()"))
+	require.Contains(t, report.MissingMethods, "getOwner()")
+	require.Len(t, report.MissingMethods, 4)
+}
+
+func TestExtractSelectorsShortPush(t *testing.T) {
+	// solc pushes the selector 0x00fdd58e of balanceOf(address,uint256) with PUSH3.
+	selector := MethodSelector("balanceOf(address,uint256)")
+	require.Equal(t, [4]byte{0x00, 0xfd, 0xd5, 0x8e}, selector)
+	code := append([]byte{byte(vm.DUP1), byte(vm.PUSH3)}, selector[1:]...)
+	code = append(code, byte(vm.EQ))
+	require.True(t, ExtractSelectors(code)[selector])
+
+	// A PUSH4 which ends exactly at the end of the code.
+	decimals := MethodSelector("decimals()")
+	code = append([]byte{byte(vm.PUSH4)}, decimals[:]...)
+	require.True(t, ExtractSelectors(code)[decimals])
+	require.Empty(t, ExtractSelectors(code[:4]))
+
+	code = append([]byte{byte(vm.PUSH32)}, EIP1967ImplementationSlot.Bytes()...)
+	require.True(t, containsPush32(code, EIP1967ImplementationSlot))
+}
+
+func TestAnalyzeBytecodeSkipsPushData(t *testing.T) {
+	// The selector of getOwner hidden inside a PUSH32 operand must not be reported.
+	selector := MethodSelector("getOwner()")
+	operand := make([]byte, 32)
+	copy(operand[10:], append([]byte{byte(vm.PUSH4)}, selector[:]...))
+	code := append([]byte{byte(vm.PUSH32)}, operand...)
+	require.Contains(t, AnalyzeBytecode(code).MissingMethods, "getOwner()")
+
+	code = append([]byte{byte(vm.PUSH32)}, EIP1967ImplementationSlot.Bytes()...)
+	require.True(t, AnalyzeBytecode(code).IsProxy)
+	require.False(t, AnalyzeBytecode(common.Hex2Bytes("7f")).IsProxy)
+}
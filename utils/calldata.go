@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	bindconst "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/contracts/bep20"
+	"github.com/binance-chain/token-bind-tool/contracts/ownable"
+	"github.com/binance-chain/token-bind-tool/contracts/tokenhub"
+)
+
+// calldataABIs are the shipped ABIs used to decode calldata, in lookup order.
+var calldataABIs = loadABIs(bep20.Bep20ABI, ownable.OwnableABI, bindconst.TokenManagerABI, tokenhub.TokenhubABI, bindconst.CanonicalUpgradeableBEP20, bindconst.UpgradeableProxyABI, bindconst.BEP20TemplateABI)
+
+// DecodedArg is one decoded argument of a call.
+type DecodedArg struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// DecodedCall is calldata decoded back into a method and its arguments.
+type DecodedCall struct {
+	Method string        `json:"method"`
+	Args   []*DecodedArg `json:"args"`
+}
+
+func (call *DecodedCall) String() string {
+	args := make([]string, 0, len(call.Args))
+	for _, arg := range call.Args {
+		args = append(args, fmt.Sprintf("%s=%s", arg.Name, arg.Value))
+	}
+	return fmt.Sprintf("%s(%s)", call.Method, strings.Join(args, ", "))
+}
+
+func loadABIs(abiJSONs ...string) []abi.ABI {
+	abis := make([]abi.ABI, 0, len(abiJSONs))
+	for _, abiJSON := range abiJSONs {
+		parsed, err := abi.JSON(strings.NewReader(abiJSON))
+		if err != nil {
+			panic(fmt.Sprintf("invalid shipped abi: %s", err.Error()))
+		}
+		abis = append(abis, parsed)
+	}
+	return abis
+}
+
+// DecodeCalldata decodes the calldata of a method call with the shipped ABIs.
+func DecodeCalldata(data []byte) (*DecodedCall, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("calldata is shorter than a method selector")
+	}
+	for idx := range calldataABIs {
+		method, err := calldataABIs[idx].MethodById(data[:4])
+		if err != nil {
+			continue
+		}
+		values, err := method.Inputs.UnpackValues(data[4:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode arguments of %s: %s", method.Name, err.Error())
+		}
+		return &DecodedCall{Method: method.Name, Args: decodedArgs(method.Inputs, values)}, nil
+	}
+	return nil, fmt.Errorf("unknown method selector %s", hexutil.Encode(data[:4]))
+}
+
+// creationCode is a contract creation code shipped with the tool, which deployments are decoded against.
+type creationCode struct {
+	name     string
+	byteCode string
+	abiJSON  string
+}
+
+// creationCodes are the shipped creation codes. The BEP20 template is skipped while its byte code is not generated.
+var creationCodes = []creationCode{
+	{name: "UpgradeableProxy", byteCode: bindconst.CanonicalUpgradeableBEP20BytesCode, abiJSON: bindconst.UpgradeableProxyABI},
+	{name: "BEP20Token", byteCode: bindconst.BEP20TemplateBytesCode, abiJSON: bindconst.BEP20TemplateABI},
+}
+
+// DecodeCreation decodes the constructor arguments of a deployment of a creation code shipped with the tool, the
+// canonical upgradeable BEP20 proxy or the BEP20 template. The initialize call passed to the proxy is decoded as well.
+func DecodeCreation(data []byte) (*DecodedCall, error) {
+	for _, creation := range creationCodes {
+		if len(creation.byteCode) == 0 {
+			continue
+		}
+		code, err := hex.DecodeString(creation.byteCode)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) < len(code) || !strings.EqualFold(hex.EncodeToString(data[:len(code)]), creation.byteCode) {
+			continue
+		}
+		constructor := loadABIs(creation.abiJSON)[0].Constructor
+		values, err := constructor.Inputs.UnpackValues(data[len(code):])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode constructor arguments: %s", err.Error())
+		}
+		args := decodedArgs(constructor.Inputs, values)
+		for idx, value := range values {
+			if initData, ok := value.([]byte); ok && len(initData) != 0 {
+				if initCall, err := DecodeCalldata(initData); err == nil {
+					args[idx].Value = initCall.String()
+				}
+			}
+		}
+		return &DecodedCall{Method: creation.name + ".constructor", Args: args}, nil
+	}
+	return nil, fmt.Errorf("the contract creation code of %d bytes is not shipped with the tool, constructor arguments are not decoded", len(data))
+}
+
+func decodedArgs(inputs abi.Arguments, values []interface{}) []*DecodedArg {
+	args := make([]*DecodedArg, 0, len(values))
+	for idx, value := range values {
+		name := inputs[idx].Name
+		if len(name) == 0 {
+			name = fmt.Sprintf("arg%d", idx)
+		}
+		args = append(args, &DecodedArg{Name: name, Type: inputs[idx].Type.String(), Value: formatArgValue(value)})
+	}
+	return args
+}
+
+func formatArgValue(value interface{}) string {
+	switch v := value.(type) {
+	case common.Address:
+		return v.String()
+	case *big.Int:
+		return v.String()
+	case []byte:
+		return hexutil.Encode(v)
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	bindconst "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/contracts/bep20"
+)
+
+func TestDecodeCalldata(t *testing.T) {
+	bep20ABI, err := abi.JSON(strings.NewReader(bep20.Bep20ABI))
+	require.NoError(t, err)
+	data, err := bep20ABI.Pack("approve", bindconst.TokenManagerContractAddr, big.NewInt(1000))
+	require.NoError(t, err)
+	call, err := DecodeCalldata(data)
+	require.NoError(t, err)
+	require.Equal(t, "approve", call.Method)
+	require.Len(t, call.Args, 2)
+	require.Equal(t, bindconst.TokenManagerContractAddr.String(), call.Args[0].Value)
+	require.Equal(t, "1000", call.Args[1].Value)
+
+	tokenManagerABI, err := abi.JSON(strings.NewReader(bindconst.TokenManagerABI))
+	require.NoError(t, err)
+	data, err = tokenManagerABI.Pack("approveBind", common.HexToAddress("0x01"), "ABC-123")
+	require.NoError(t, err)
+	call, err = DecodeCalldata(data)
+	require.NoError(t, err)
+	require.Equal(t, "approveBind", call.Method)
+	require.Equal(t, "ABC-123", call.Args[1].Value)
+
+	_, err = DecodeCalldata([]byte{0xde, 0xad, 0xbe, 0xef})
+	require.Error(t, err)
+}
+
+func TestDecodeCreation(t *testing.T) {
+	canonicalABI, err := abi.JSON(strings.NewReader(bindconst.CanonicalUpgradeableBEP20))
+	require.NoError(t, err)
+	initData, err := canonicalABI.Pack("initialize", "Token", "ABC", uint8(18), big.NewInt(100), true, common.HexToAddress("0x02"))
+	require.NoError(t, err)
+	proxyABI, err := abi.JSON(strings.NewReader(bindconst.UpgradeableProxyABI))
+	require.NoError(t, err)
+	constructorData, err := proxyABI.Pack("", common.HexToAddress("0x03"), common.HexToAddress("0x04"), initData)
+	require.NoError(t, err)
+	code, err := hex.DecodeString(bindconst.CanonicalUpgradeableBEP20BytesCode)
+	require.NoError(t, err)
+
+	call, err := DecodeCreation(append(code, constructorData...))
+	require.NoError(t, err)
+	require.Len(t, call.Args, 3)
+	require.Equal(t, common.HexToAddress("0x03").String(), call.Args[0].Value)
+	require.True(t, strings.HasPrefix(call.Args[2].Value, "initialize("))
+
+	_, err = DecodeCreation([]byte{0x60, 0x80})
+	require.Error(t, err)
+}
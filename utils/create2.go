@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	bindconst "github.com/binance-chain/token-bind-tool/const"
+	bindtypes "github.com/binance-chain/token-bind-tool/types"
+)
+
+// The deterministic deployment proxy (https://github.com/Arachnid/deterministic-deployment-proxy). It is deployed by
+// a presigned transaction without chain id, so it lives at the same address on every chain which accepts it. Called
+// with a 32 bytes salt followed by the init code, it creates the contract with CREATE2 and returns its address.
+var (
+	Create2FactoryAddr     = common.HexToAddress("0x4e59b44847b379578588920ca78fbf26c0b4956c")
+	Create2FactoryDeployer = common.HexToAddress("0x3fab184622dc19b6109349b94811493bf2a45362")
+)
+
+const (
+	create2FactoryDeployTx = "0xf8a58085174876e800830186a08080b853604580600e600039806000f350fe7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe03601600081602082378035828234f58015156039578182fd5b8082525050506014600cf31ba02222222222222222222222222222222222222222222222222222222222222222a02222222222222222222222222222222222222222222222222222222222222222"
+	// txMinedTimeout is how long to wait for a transaction to be mined.
+	txMinedTimeout = 2 * time.Minute
+)
+
+// ParseSalt parses a CREATE2 salt. A 0x prefixed hex string of up to 32 bytes is left padded to 32 bytes, any other
+// string is hashed with keccak256, so that a label like "MyToken-v1" can be used as a salt.
+func ParseSalt(salt string) ([32]byte, error) {
+	var result [32]byte
+	if len(salt) == 0 {
+		return result, fmt.Errorf("empty salt")
+	}
+	if strings.HasPrefix(salt, "0x") {
+		data, err := hexutil.Decode(salt)
+		if err != nil {
+			return result, fmt.Errorf("invalid salt %q: %s", salt, err.Error())
+		}
+		if len(data) > len(result) {
+			return result, fmt.Errorf("invalid salt %q, longer than 32 bytes", salt)
+		}
+		copy(result[len(result)-len(data):], data)
+		return result, nil
+	}
+	copy(result[:], crypto.Keccak256([]byte(salt)))
+	return result, nil
+}
+
+// PredictCreate2Address returns the address factory creates initCode at with salt.
+func PredictCreate2Address(factory common.Address, salt [32]byte, initCode []byte) common.Address {
+	return crypto.CreateAddress2(factory, salt, crypto.Keccak256(initCode))
+}
+
+// Create2Calldata is the calldata of the deterministic deployment proxy to create initCode with salt.
+func Create2Calldata(salt [32]byte, initCode []byte) []byte {
+	return append(append([]byte{}, salt[:]...), initCode...)
+}
+
+// create2FactoryDeployment decodes the presigned deployment transaction of the deterministic deployment proxy.
+func create2FactoryDeployment() (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(hexutil.MustDecode(create2FactoryDeployTx), tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Create2FactoryDeployCost is the BNB the factory deployer must hold to send the presigned deployment transaction.
+func Create2FactoryDeployCost() *big.Int {
+	tx, err := create2FactoryDeployment()
+	if err != nil {
+		panic(err)
+	}
+	return tx.Cost()
+}
+
+// EnsureCreate2Factory deploys the deterministic deployment proxy at Create2FactoryAddr when there is no code there,
+// which is the case on a new local or private chain. account funds the factory deployer with the deployment cost.
+func EnsureCreate2Factory(ethClient *ethclient.Client, wallet *keystore.KeyStore, account accounts.Account, chainId *big.Int) error {
+	code, err := ethClient.CodeAt(context.Background(), Create2FactoryAddr, nil)
+	if err != nil {
+		return err
+	}
+	if len(code) != 0 {
+		return nil
+	}
+	fmt.Println(fmt.Sprintf("No CREATE2 factory at %s, deploy it", Create2FactoryAddr.String()))
+	deployTx, err := create2FactoryDeployment()
+	if err != nil {
+		return err
+	}
+	balance, err := ethClient.BalanceAt(context.Background(), Create2FactoryDeployer, nil)
+	if err != nil {
+		return err
+	}
+	if missing := new(big.Int).Sub(deployTx.Cost(), balance); missing.Sign() > 0 {
+		fundTx, err := sendBNB(ethClient, wallet, account, Create2FactoryDeployer, missing, chainId)
+		if err != nil {
+			return fmt.Errorf("failed to fund the CREATE2 factory deployer: %s", err.Error())
+		}
+		if err := waitMined(ethClient, fundTx); err != nil {
+			return err
+		}
+	}
+	if err := ethClient.SendTransaction(context.Background(), deployTx); err != nil {
+		return fmt.Errorf("failed to deploy the CREATE2 factory, the node must accept transactions without chain id: %s", err.Error())
+	}
+	if err := waitMined(ethClient, deployTx); err != nil {
+		return err
+	}
+	PrintTxExplorerUrl("Deploy CREATE2 factory txHash", deployTx.Hash().String(), chainId)
+	return nil
+}
+
+// DeployContractCreate2 creates initCode with salt through factory and returns the transaction hash and the contract
+// address. It fails if there is already code at the address.
+func DeployContractCreate2(ethClient *ethclient.Client, wallet *keystore.KeyStore, account accounts.Account, factory common.Address, salt [32]byte, initCode []byte, gasLimit uint64, gasPrice *big.Int, chainId *big.Int) (common.Hash, common.Address, error) {
+	contractAddr := PredictCreate2Address(factory, salt, initCode)
+	code, err := ethClient.CodeAt(context.Background(), contractAddr, nil)
+	if err != nil {
+		return common.Hash{}, common.Address{}, err
+	}
+	if len(code) != 0 {
+		return common.Hash{}, common.Address{}, fmt.Errorf("a contract is already deployed at %s with this salt and init code", contractAddr.String())
+	}
+	nonce, err := ethClient.PendingNonceAt(context.Background(), account.Address)
+	if err != nil {
+		return common.Hash{}, common.Address{}, err
+	}
+	tx := types.NewTransaction(nonce, factory, big.NewInt(0), gasLimit, gasPrice, Create2Calldata(salt, initCode))
+	signTx, err := wallet.SignTx(account, tx, chainId)
+	if err != nil {
+		return common.Hash{}, common.Address{}, err
+	}
+	if err := ethClient.SendTransaction(context.Background(), signTx); err != nil {
+		return common.Hash{}, common.Address{}, err
+	}
+	if err := waitMined(ethClient, signTx); err != nil {
+		return signTx.Hash(), common.Address{}, err
+	}
+	code, err = ethClient.CodeAt(context.Background(), contractAddr, nil)
+	if err != nil {
+		return signTx.Hash(), common.Address{}, err
+	}
+	if len(code) == 0 {
+		return signTx.Hash(), common.Address{}, fmt.Errorf("CREATE2 deployment %s left no code at %s", signTx.Hash().String(), contractAddr.String())
+	}
+	return signTx.Hash(), contractAddr, nil
+}
+
+func sendBNB(ethClient *ethclient.Client, wallet *keystore.KeyStore, account accounts.Account, recipient common.Address, amount *big.Int, chainId *big.Int) (*types.Transaction, error) {
+	nonce, err := ethClient.PendingNonceAt(context.Background(), account.Address)
+	if err != nil {
+		return nil, err
+	}
+	gasLimit := hexutil.Uint64(21000)
+	gasPrice := hexutil.Big(*big.NewInt(bindconst.DefaultGasPrice))
+	amountBig := hexutil.Big(*amount)
+	nonceUint64 := hexutil.Uint64(nonce)
+	tx := toTransaction(&bindtypes.SendTxArgs{
+		From:     account.Address,
+		To:       &recipient,
+		Gas:      &gasLimit,
+		GasPrice: &gasPrice,
+		Value:    &amountBig,
+		Nonce:    &nonceUint64,
+	})
+	signTx, err := wallet.SignTx(account, tx, chainId)
+	if err != nil {
+		return nil, err
+	}
+	return signTx, ethClient.SendTransaction(context.Background(), signTx)
+}
+
+// waitMined waits until tx is mined and fails if it reverted.
+func waitMined(ethClient *ethclient.Client, tx *types.Transaction) error {
+	ctx, cancel := context.WithTimeout(context.Background(), txMinedTimeout)
+	defer cancel()
+	receipt, err := bind.WaitMined(ctx, ethClient, tx)
+	if err != nil {
+		return fmt.Errorf("transaction %s is not mined: %s", tx.Hash().String(), err.Error())
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("transaction %s failed", tx.Hash().String())
+	}
+	return nil
+}
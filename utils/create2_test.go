@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPredictCreate2Address(t *testing.T) {
+	// Examples of EIP-1014.
+	require.Equal(t, common.HexToAddress("0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38"),
+		PredictCreate2Address(common.Address{}, [32]byte{}, []byte{0x00}))
+	require.Equal(t, common.HexToAddress("0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3"),
+		PredictCreate2Address(common.HexToAddress("0xdeadbeef00000000000000000000000000000000"), [32]byte{}, []byte{0x00}))
+}
+
+func TestParseSalt(t *testing.T) {
+	salt, err := ParseSalt("0x01")
+	require.NoError(t, err)
+	require.Equal(t, common.HexToHash("0x01"), common.Hash(salt))
+
+	salt, err = ParseSalt("MyToken-v1")
+	require.NoError(t, err)
+	require.Equal(t, crypto.Keccak256Hash([]byte("MyToken-v1")), common.Hash(salt))
+
+	_, err = ParseSalt("0x" + "00112233445566778899aabbccddeeff00112233445566778899aabbccddeeff00")
+	require.Error(t, err)
+	_, err = ParseSalt("0xzz")
+	require.Error(t, err)
+	_, err = ParseSalt("")
+	require.Error(t, err)
+}
+
+func TestCreate2FactoryDeployment(t *testing.T) {
+	tx, err := create2FactoryDeployment()
+	require.NoError(t, err)
+	sender, err := types.Sender(types.HomesteadSigner{}, tx)
+	require.NoError(t, err)
+	require.Equal(t, Create2FactoryDeployer, sender)
+	require.Equal(t, Create2FactoryAddr, crypto.CreateAddress(sender, 0))
+	require.Equal(t, "10000000000000000", Create2FactoryDeployCost().String())
+}
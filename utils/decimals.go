@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/shopspring/decimal"
+
+	bindconst "github.com/binance-chain/token-bind-tool/const"
+)
+
+// MaxBEP20Decimals is the largest number of decimals with which one whole token still fits in a uint256.
+const MaxBEP20Decimals = 77
+
+// Rounding tells a decimals conversion what to do with the digits which do not fit into fewer decimals.
+type Rounding int
+
+const (
+	// RoundExact fails the conversion when any non-zero digit would be dropped.
+	RoundExact Rounding = iota
+	// RoundDown drops the digits, rounding towards zero.
+	RoundDown
+	// RoundUp rounds away from zero when any non-zero digit is dropped.
+	RoundUp
+)
+
+// ConvertDecimals converts a non-negative amount counted with fromDecimals into the same amount counted with
+// toDecimals. Scaling up is always exact. Scaling down follows rounding.
+func ConvertDecimals(amount *big.Int, fromDecimals, toDecimals int64, rounding Rounding) (*big.Int, error) {
+	if amount.Sign() < 0 {
+		return nil, fmt.Errorf("amount %s must not be negative", amount.String())
+	}
+	for _, decimals := range []int64{fromDecimals, toDecimals} {
+		if decimals < 0 || decimals > MaxBEP20Decimals {
+			return nil, fmt.Errorf("decimals %d out of range, expect 0 to %d", decimals, MaxBEP20Decimals)
+		}
+	}
+	if toDecimals >= fromDecimals {
+		return new(big.Int).Mul(amount, pow10(toDecimals-fromDecimals)), nil
+	}
+	quotient, remainder := new(big.Int).QuoRem(amount, pow10(fromDecimals-toDecimals), new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient, nil
+	}
+	switch rounding {
+	case RoundDown:
+		return quotient, nil
+	case RoundUp:
+		return quotient.Add(quotient, big.NewInt(1)), nil
+	default:
+		return nil, fmt.Errorf("%s with %d decimals can not be represented with %d decimals without losing %s", amount.String(), fromDecimals, toDecimals, remainder.String())
+	}
+}
+
+// ConvertToBEP20Amount converts a BEP2 amount, which has 8 decimals, into a BEP20 amount with decimals.
+func ConvertToBEP20Amount(amount *big.Int, decimals int64, rounding Rounding) (*big.Int, error) {
+	return ConvertDecimals(amount, bindconst.BcDecimals, decimals, rounding)
+}
+
+// ConvertToBEP2Amount converts a BEP20 amount with decimals into a BEP2 amount, which has 8 decimals.
+func ConvertToBEP2Amount(amount *big.Int, decimals int64, rounding Rounding) (*big.Int, error) {
+	return ConvertDecimals(amount, decimals, bindconst.BcDecimals, rounding)
+}
+
+func pow10(exp int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil)
+}
+
+// SupplyDifference returns the BEP2 total supply, a decimal string like "1000.00000000", scaled to the BEP20 decimals
+// minus bep20TotalSupply. It is positive when BEP20 tokens have to be minted to match, negative when they have to be
+// burnt.
+func SupplyDifference(bep2TotalSupply string, bep20TotalSupply *big.Int, decimals int64) (*big.Int, error) {
+	supply, err := decimal.NewFromString(bep2TotalSupply)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BEP2 total supply %q: %s", bep2TotalSupply, err.Error())
+	}
+	supply = supply.Shift(bindconst.BcDecimals)
+	if !supply.Equal(supply.Truncate(0)) {
+		return nil, fmt.Errorf("invalid BEP2 total supply %q, more than %d decimals", bep2TotalSupply, bindconst.BcDecimals)
+	}
+	bep20Supply, err := ConvertToBEP20Amount(supply.BigInt(), decimals, RoundExact)
+	if err != nil {
+		return nil, err
+	}
+	return bep20Supply.Sub(bep20Supply, bep20TotalSupply), nil
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+	"testing/quick"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+// quickDecimals maps an arbitrary byte onto the supported decimals range.
+func quickDecimals(d uint8) int64 {
+	return int64(d) % (MaxBEP20Decimals + 1)
+}
+
+func TestConvertDecimals(t *testing.T) {
+	amount, err := ConvertToBEP20Amount(big.NewInt(150000000), 18, RoundExact)
+	require.NoError(t, err)
+	require.Equal(t, "1500000000000000000", amount.String())
+
+	amount, err = ConvertToBEP20Amount(big.NewInt(1), 77, RoundExact)
+	require.NoError(t, err)
+	require.Equal(t, new(big.Int).Exp(big.NewInt(10), big.NewInt(69), nil), amount)
+
+	_, err = ConvertToBEP20Amount(big.NewInt(123456789), 2, RoundExact)
+	require.Error(t, err)
+	amount, err = ConvertToBEP20Amount(big.NewInt(123456789), 2, RoundDown)
+	require.NoError(t, err)
+	require.Equal(t, "123", amount.String())
+	amount, err = ConvertToBEP20Amount(big.NewInt(123456789), 2, RoundUp)
+	require.NoError(t, err)
+	require.Equal(t, "124", amount.String())
+
+	_, err = ConvertToBEP20Amount(big.NewInt(1), 78, RoundExact)
+	require.Error(t, err)
+	_, err = ConvertToBEP20Amount(big.NewInt(-1), 18, RoundExact)
+	require.Error(t, err)
+}
+
+func TestConvertDecimalsProperties(t *testing.T) {
+	// Scaling up then down again gives the amount back.
+	roundTrip := func(raw uint64, d uint8) bool {
+		decimals := quickDecimals(d)
+		if decimals < 8 {
+			decimals += 8
+		}
+		bep20Amount, err := ConvertToBEP20Amount(new(big.Int).SetUint64(raw), decimals, RoundExact)
+		if err != nil {
+			return false
+		}
+		bep2Amount, err := ConvertToBEP2Amount(bep20Amount, decimals, RoundExact)
+		return err == nil && bep2Amount.Cmp(new(big.Int).SetUint64(raw)) == 0
+	}
+	require.NoError(t, quick.Check(roundTrip, nil))
+
+	// The conversion matches decimal arithmetic: exact fails iff digits are dropped, and down <= value <= up with the
+	// two bounds at most one unit apart.
+	bounds := func(raw uint64, d uint8) bool {
+		decimals := quickDecimals(d)
+		amount := new(big.Int).SetUint64(raw)
+		value := decimal.NewFromBigInt(amount, -8).Shift(int32(decimals))
+		down, errDown := ConvertToBEP20Amount(amount, decimals, RoundDown)
+		up, errUp := ConvertToBEP20Amount(amount, decimals, RoundUp)
+		exact, errExact := ConvertToBEP20Amount(amount, decimals, RoundExact)
+		if errDown != nil || errUp != nil {
+			return false
+		}
+		isInteger := value.Equal(value.Truncate(0))
+		if isInteger != (errExact == nil) {
+			return false
+		}
+		if isInteger {
+			return exact.Cmp(value.BigInt()) == 0 && down.Cmp(exact) == 0 && up.Cmp(exact) == 0
+		}
+		return decimal.NewFromBigInt(down, 0).LessThan(value) &&
+			decimal.NewFromBigInt(up, 0).GreaterThan(value) &&
+			new(big.Int).Sub(up, down).Cmp(big.NewInt(1)) == 0
+	}
+	require.NoError(t, quick.Check(bounds, &quick.Config{MaxCount: 1000}))
+
+	// Converting never decreases when the amount grows.
+	monotonic := func(a, b uint64, d uint8) bool {
+		if a > b {
+			a, b = b, a
+		}
+		decimals := quickDecimals(d)
+		lo, _ := ConvertToBEP20Amount(new(big.Int).SetUint64(a), decimals, RoundDown)
+		hi, _ := ConvertToBEP20Amount(new(big.Int).SetUint64(b), decimals, RoundDown)
+		return lo.Cmp(hi) <= 0
+	}
+	require.NoError(t, quick.Check(monotonic, nil))
+}
+
+func TestSupplyDifference(t *testing.T) {
+	bep20Supply, _ := new(big.Int).SetString("1500000000000000000000", 10)
+	diff, err := SupplyDifference("1500.00000000", bep20Supply, 18)
+	require.NoError(t, err)
+	require.Equal(t, "0", diff.String())
+
+	diff, err = SupplyDifference("1500.5", bep20Supply, 18)
+	require.NoError(t, err)
+	require.Equal(t, "500000000000000000", diff.String())
+
+	diff, err = SupplyDifference("1499", bep20Supply, 18)
+	require.NoError(t, err)
+	require.Equal(t, "-1000000000000000000", diff.String())
+
+	_, err = SupplyDifference("1500.00000001", bep20Supply, 6)
+	require.Error(t, err)
+	_, err = SupplyDifference("0.000000001", bep20Supply, 18)
+	require.Error(t, err)
+	_, err = SupplyDifference("abc", bep20Supply, 18)
+	require.Error(t, err)
+}
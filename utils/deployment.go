@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var deploymentNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Deployment is the record of a contract deployment, written to {deployments dir}/{network}/{name}.json.
+type Deployment struct {
+	Name     string         `json:"name"`
+	Network  string         `json:"network"`
+	ChainId  *hexutil.Big   `json:"chain_id"`
+	Address  common.Address `json:"address"`
+	TxHash   common.Hash    `json:"tx_hash"`
+	Block    uint64         `json:"block"`
+	Deployer common.Address `json:"deployer"`
+	GasUsed  uint64         `json:"gas_used"`
+	// InitCodeHash is the keccak256 of the creation byte code with the constructor arguments.
+	InitCodeHash common.Hash `json:"init_code_hash"`
+	// DeployedCodeHash is the keccak256 of the runtime byte code at Address.
+	DeployedCodeHash common.Hash `json:"deployed_code_hash"`
+	// ConstructorArgs are the constructor arguments as given in the deploy config.
+	ConstructorArgs []json.RawMessage `json:"constructor_args,omitempty"`
+	// EncodedConstructorArgs are the packed constructor arguments at the end of the creation byte code.
+	EncodedConstructorArgs hexutil.Bytes   `json:"encoded_constructor_args,omitempty"`
+	ABI                    json.RawMessage `json:"abi,omitempty"`
+	// Create2Factory and Salt are set for a CREATE2 deployment.
+	Create2Factory *common.Address `json:"create2_factory,omitempty"`
+	Salt           hexutil.Bytes   `json:"salt,omitempty"`
+	// ArtifactHash is the sha256 of the build artifact the contract was deployed from.
+	ArtifactHash string `json:"artifact_sha256,omitempty"`
+	// Timestamp is the time of the block the deployment is in.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewDeployment reads the receipt and the block of the deployment txHash, which created contractAddr from initCode.
+func NewDeployment(ethClient *ethclient.Client, network string, chainId *big.Int, deployer common.Address, txHash common.Hash, contractAddr common.Address, initCode []byte) (*Deployment, error) {
+	receipt, err := ethClient.TransactionReceipt(context.Background(), txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the receipt of %s: %s", txHash.String(), err.Error())
+	}
+	header, err := ethClient.HeaderByNumber(context.Background(), receipt.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	code, err := ethClient.CodeAt(context.Background(), contractAddr, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Deployment{
+		Network:          network,
+		ChainId:          (*hexutil.Big)(chainId),
+		Address:          contractAddr,
+		TxHash:           txHash,
+		Block:            receipt.BlockNumber.Uint64(),
+		Deployer:         deployer,
+		GasUsed:          receipt.GasUsed,
+		InitCodeHash:     crypto.Keccak256Hash(initCode),
+		DeployedCodeHash: crypto.Keccak256Hash(code),
+		Timestamp:        time.Unix(int64(header.Time), 0).UTC(),
+	}, nil
+}
+
+// DeploymentPath is the path of the record of the contract name deployed on network.
+func DeploymentPath(dir, network, name string) (string, error) {
+	if !deploymentNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid deployment name %q, expect letters, digits, '_', '.' and '-'", name)
+	}
+	return filepath.Join(dir, network, name+".json"), nil
+}
+
+// SaveDeployment writes deployment under dir, replacing the record of a previous deployment with the same name, and
+// returns its path.
+func SaveDeployment(dir string, deployment *Deployment) (string, error) {
+	path, err := DeploymentPath(dir, deployment.Network, deployment.Name)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(deployment, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create the deployments directory: %s", err.Error())
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write the deployment record: %s", err.Error())
+	}
+	return path, nil
+}
+
+// LoadDeployment reads the record of the contract name deployed on network.
+func LoadDeployment(dir, network, name string) (*Deployment, error) {
+	path, err := DeploymentPath(dir, network, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no deployment of %s on %s in %s", name, network, dir)
+		}
+		return nil, fmt.Errorf("failed to read the deployment record: %s", err.Error())
+	}
+	deployment := &Deployment{}
+	if err := json.Unmarshal(data, deployment); err != nil {
+		return nil, fmt.Errorf("invalid deployment record %s: %s", path, err.Error())
+	}
+	return deployment, nil
+}
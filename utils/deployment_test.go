@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveDeployment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deployments")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	deployment := &Deployment{
+		Name:                   "MyToken",
+		Network:                "testnet",
+		ChainId:                (*hexutil.Big)(big.NewInt(97)),
+		Address:                common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		TxHash:                 common.HexToHash("0x01"),
+		Block:                  100,
+		GasUsed:                21000,
+		ConstructorArgs:        []json.RawMessage{json.RawMessage(`18`)},
+		EncodedConstructorArgs: hexutil.MustDecode("0x12"),
+		Timestamp:              time.Unix(1600000000, 0).UTC(),
+	}
+	path, err := SaveDeployment(dir, deployment)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "testnet", "MyToken.json"), path)
+
+	loaded, err := LoadDeployment(dir, "testnet", "MyToken")
+	require.NoError(t, err)
+	require.Equal(t, deployment, loaded)
+
+	_, err = LoadDeployment(dir, "mainnet", "MyToken")
+	require.EqualError(t, err, "no deployment of MyToken on mainnet in "+dir)
+
+	deployment.Name = "../MyToken"
+	_, err = SaveDeployment(dir, deployment)
+	require.Error(t, err)
+}
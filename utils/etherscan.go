@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EtherscanClient submits verification bundles to an Etherscan compatible API like https://api.bscscan.com/api.
+type EtherscanClient struct {
+	ApiUrl string
+	ApiKey string
+
+	httpClient *http.Client
+}
+
+type etherscanResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Result  string `json:"result"`
+}
+
+func NewEtherscanClient(apiUrl, apiKey string) *EtherscanClient {
+	return &EtherscanClient{
+		ApiUrl:     apiUrl,
+		ApiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SubmitVerification submits bundle for verification and returns the guid to check its status with.
+func (client *EtherscanClient) SubmitVerification(bundle *VerificationBundle) (string, error) {
+	form := url.Values{
+		"apikey":                {client.ApiKey},
+		"module":                {"contract"},
+		"action":                {"verifysourcecode"},
+		"contractaddress":       {bundle.ContractAddress.String()},
+		"sourceCode":            {string(bundle.StandardJSONInput)},
+		"codeformat":            {"solidity-standard-json-input"},
+		"contractname":          {bundle.ContractName},
+		"compilerversion":       {bundle.CompilerVersion},
+		"optimizationUsed":      {"0"},
+		"runs":                  {strconv.Itoa(bundle.OptimizerRuns)},
+		"constructorArguements": {bundle.ConstructorArgs},
+	}
+	if bundle.Optimizer {
+		form.Set("optimizationUsed", "1")
+	}
+	if len(bundle.EVMVersion) != 0 {
+		form.Set("evmversion", bundle.EVMVersion)
+	}
+	resp, err := client.httpClient.PostForm(client.ApiUrl, form)
+	if err != nil {
+		return "", err
+	}
+	result, err := decodeEtherscanResponse(resp)
+	if err != nil {
+		return "", err
+	}
+	if result.Status != "1" {
+		return "", fmt.Errorf("verification refused: %s", result.Result)
+	}
+	return result.Result, nil
+}
+
+// CheckVerification returns the status of the verification guid, and whether it is still pending. A failed
+// verification is an error.
+func (client *EtherscanClient) CheckVerification(guid string) (string, bool, error) {
+	query := url.Values{
+		"apikey": {client.ApiKey},
+		"module": {"contract"},
+		"action": {"checkverifystatus"},
+		"guid":   {guid},
+	}
+	resp, err := client.httpClient.Get(client.ApiUrl + "?" + query.Encode())
+	if err != nil {
+		return "", false, err
+	}
+	result, err := decodeEtherscanResponse(resp)
+	if err != nil {
+		return "", false, err
+	}
+	switch {
+	case result.Status == "1", strings.Contains(result.Result, "Already Verified"):
+		return result.Result, false, nil
+	case strings.HasPrefix(result.Result, "Pending"):
+		return result.Result, true, nil
+	default:
+		return "", false, fmt.Errorf("verification failed: %s", result.Result)
+	}
+}
+
+func decodeEtherscanResponse(resp *http.Response) (*etherscanResponse, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status: %s", resp.Status)
+	}
+	var result etherscanResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("unexpected response: %s", err.Error())
+	}
+	return &result, nil
+}
@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// CallArgs is a message executed with eth_call. To is nil for a contract creation.
+type CallArgs struct {
+	From  common.Address
+	To    *common.Address
+	Value *big.Int
+	Data  []byte
+	Gas   uint64
+}
+
+// OverrideAccount replaces parts of an account state for the duration of an eth_call.
+type OverrideAccount struct {
+	Balance   *hexutil.Big                `json:"balance,omitempty"`
+	Code      *hexutil.Bytes              `json:"code,omitempty"`
+	StateDiff map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+}
+
+type StateOverride map[common.Address]OverrideAccount
+
+// RevertError is returned by EthCall when the call reverts. Data holds the raw revert data if the node returned it.
+type RevertError struct {
+	Message string
+	Data    []byte
+}
+
+func (e *RevertError) Error() string {
+	if reason := DecodeRevertReason(e.Data); len(reason) != 0 {
+		return fmt.Sprintf("%s: %s", e.Message, reason)
+	}
+	return e.Message
+}
+
+type jsonRPCRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type jsonRPCResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	} `json:"error"`
+}
+
+func (args *CallArgs) toArg() map[string]interface{} {
+	arg := map[string]interface{}{
+		"from": args.From,
+	}
+	if args.To != nil {
+		arg["to"] = args.To
+	}
+	if len(args.Data) > 0 {
+		arg["data"] = hexutil.Bytes(args.Data)
+	}
+	if args.Value != nil {
+		arg["value"] = (*hexutil.Big)(args.Value)
+	}
+	if args.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(args.Gas)
+	}
+	return arg
+}
+
+// EthCall executes args with eth_call at blockTag. It talks JSON-RPC over http directly, because the revert data
+// lives in the error object, which rpc.Client does not expose.
+func EthCall(rpcUrl string, args *CallArgs, blockTag string, overrides StateOverride) ([]byte, error) {
+	params := []interface{}{args.toArg(), blockTag}
+	if len(overrides) != 0 {
+		params = append(params, overrides)
+	}
+	reqData, err := json.Marshal(&jsonRPCRequest{JSONRPC: "2.0", ID: 1, Method: "eth_call", Params: params})
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(rpcUrl, "application/json", bytes.NewReader(reqData))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var rpcResp jsonRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return nil, fmt.Errorf("failed to decode eth_call response, status %s: %s", resp.Status, err.Error())
+	}
+	if rpcResp.Error != nil {
+		if !strings.Contains(rpcResp.Error.Message, "revert") {
+			return nil, fmt.Errorf("eth_call failed: %s", rpcResp.Error.Message)
+		}
+		revertErr := &RevertError{Message: rpcResp.Error.Message}
+		var dataHex string
+		if err := json.Unmarshal(rpcResp.Error.Data, &dataHex); err == nil {
+			revertErr.Data, _ = hexutil.Decode(dataHex)
+		}
+		return nil, revertErr
+	}
+	var result hexutil.Bytes
+	if err := json.Unmarshal(rpcResp.Result, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// EstimateGasWithOverrides binary searches the lowest gas limit with which the call succeeds, in the same way as
+// eth_estimateGas does, but honouring the state overrides. It returns the error of the call at the upper bound.
+func EstimateGasWithOverrides(rpcUrl string, args *CallArgs, blockTag string, overrides StateOverride, upperBound uint64) (uint64, error) {
+	call := *args
+	call.Gas = upperBound
+	if _, err := EthCall(rpcUrl, &call, blockTag, overrides); err != nil {
+		return 0, err
+	}
+	lo, hi := uint64(21000-1), upperBound
+	for lo+1 < hi {
+		mid := (lo + hi) / 2
+		call.Gas = mid
+		if _, err := EthCall(rpcUrl, &call, blockTag, overrides); err != nil {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi, nil
+}
+
+// MappingSlot returns the storage slot of mapping[key] for a solidity mapping declared at slot.
+func MappingSlot(key common.Hash, slot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(key.Bytes(), slot.Bytes())
+}
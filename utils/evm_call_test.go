@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeErrorString(t *testing.T, reason string) []byte {
+	stringType, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+	data, err := abi.Arguments{{Type: stringType}}.Pack(reason)
+	require.NoError(t, err)
+	return append(append([]byte{}, errorSelector...), data...)
+}
+
+func TestEthCallRevert(t *testing.T) {
+	revertData := encodeErrorString(t, "allowance is not enough")
+	var received []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonRPCRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		received = req.Params
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted","data":"` + hexutil.Encode(revertData) + `"}}`))
+	}))
+	defer server.Close()
+
+	to := common.HexToAddress("0x0000000000000000000000000000000000001008")
+	overrides := StateOverride{to: {StateDiff: map[common.Hash]common.Hash{{}: common.BigToHash(common.Big1)}}}
+	_, err := EthCall(server.URL, &CallArgs{To: &to}, "latest", overrides)
+	require.Error(t, err)
+	revertErr, ok := err.(*RevertError)
+	require.True(t, ok)
+	require.Equal(t, revertData, revertErr.Data)
+	require.Equal(t, "execution reverted: allowance is not enough", err.Error())
+	require.Len(t, received, 3)
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ProxyInfo is the implementation and the admin of an EIP-1967 proxy.
+type ProxyInfo struct {
+	Proxy          common.Address `json:"proxy"`
+	Implementation common.Address `json:"implementation"`
+	Admin          common.Address `json:"admin"`
+}
+
+// ReadProxyInfo reads the EIP-1967 slots of proxy from its storage. Unlike admin() and implementation() of a transparent
+// proxy, this works for any caller.
+func ReadProxyInfo(ethClient *ethclient.Client, proxy common.Address) (*ProxyInfo, error) {
+	implementation, err := ethClient.StorageAt(context.Background(), proxy, EIP1967ImplementationSlot, nil)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := ethClient.StorageAt(context.Background(), proxy, EIP1967AdminSlot, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ProxyInfo{
+		Proxy:          proxy,
+		Implementation: common.BytesToAddress(implementation),
+		Admin:          common.BytesToAddress(admin),
+	}, nil
+}
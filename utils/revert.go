@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	bindconst "github.com/binance-chain/token-bind-tool/const"
+	"github.com/binance-chain/token-bind-tool/contracts/bep20"
+	"github.com/binance-chain/token-bind-tool/contracts/ownable"
+)
+
+var (
+	errorSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+
+	panicReasons = map[uint64]string{
+		0x00: "generic compiler panic",
+		0x01: "assertion failed",
+		0x11: "arithmetic overflow or underflow",
+		0x12: "division or modulo by zero",
+		0x21: "invalid enum value",
+		0x22: "invalid storage byte array encoding",
+		0x31: "pop on an empty array",
+		0x32: "array index out of bounds",
+		0x41: "out of memory",
+		0x51: "call to a zero-initialized function",
+	}
+
+	// customErrors maps the selector of every custom error declared in the shipped ABIs to its definition.
+	customErrors = loadCustomErrors(bep20.Bep20ABI, ownable.OwnableABI, bindconst.TokenManagerABI, bindconst.CanonicalUpgradeableBEP20)
+)
+
+type customError struct {
+	Name      string
+	Arguments abi.Arguments
+}
+
+type abiErrorEntry struct {
+	Type   string                   `json:"type"`
+	Name   string                   `json:"name"`
+	Inputs []abi.ArgumentMarshaling `json:"inputs"`
+}
+
+func loadCustomErrors(abiJSONs ...string) map[string]*customError {
+	errors := make(map[string]*customError)
+	for _, abiJSON := range abiJSONs {
+		var entries []abiErrorEntry
+		if err := json.Unmarshal([]byte(abiJSON), &entries); err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.Type != "error" {
+				continue
+			}
+			var arguments abi.Arguments
+			var argTypes []string
+			valid := true
+			for _, input := range entry.Inputs {
+				argType, err := abi.NewType(input.Type, input.InternalType, input.Components)
+				if err != nil {
+					valid = false
+					break
+				}
+				arguments = append(arguments, abi.Argument{Name: input.Name, Type: argType})
+				argTypes = append(argTypes, argType.String())
+			}
+			if !valid {
+				continue
+			}
+			signature := fmt.Sprintf("%s(%s)", entry.Name, strings.Join(argTypes, ","))
+			errors[string(crypto.Keccak256([]byte(signature))[:4])] = &customError{Name: entry.Name, Arguments: arguments}
+		}
+	}
+	return errors
+}
+
+// DecodeRevertReason decodes revert data in the Error(string) and Panic(uint256) formats, or as a custom error declared
+// in one of the shipped ABIs. It returns an empty string if data matches none of them.
+func DecodeRevertReason(data []byte) string {
+	if len(data) < 4 {
+		return ""
+	}
+	selector, payload := data[:4], data[4:]
+	switch {
+	case bytes.Equal(selector, errorSelector):
+		stringType, _ := abi.NewType("string", "", nil)
+		values, err := abi.Arguments{{Type: stringType}}.UnpackValues(payload)
+		if err != nil || len(values) != 1 {
+			return ""
+		}
+		reason, _ := values[0].(string)
+		return reason
+	case bytes.Equal(selector, panicSelector):
+		if len(payload) != 32 {
+			return ""
+		}
+		code := new(big.Int).SetBytes(payload)
+		if reason, ok := panicReasons[code.Uint64()]; ok && code.IsUint64() {
+			return fmt.Sprintf("panic 0x%x: %s", code, reason)
+		}
+		return fmt.Sprintf("panic 0x%x", code)
+	}
+	if customErr, ok := customErrors[string(selector)]; ok {
+		values, err := customErr.Arguments.UnpackValues(payload)
+		if err != nil {
+			return customErr.Name
+		}
+		var args []string
+		for _, value := range values {
+			args = append(args, fmt.Sprintf("%v", value))
+		}
+		return fmt.Sprintf("%s(%s)", customErr.Name, strings.Join(args, ", "))
+	}
+	return ""
+}
+
+// ReplayFailedTx re-executes a mined transaction with eth_call on the state of the parent of its block and returns why
+// it failed. Transactions mined before it in the same block are not applied, which is close enough for a reason.
+func ReplayFailedTx(rpcUrl string, tx *types.Transaction, blockNumber *big.Int, chainId *big.Int) string {
+	from, err := types.Sender(types.NewEIP155Signer(chainId), tx)
+	if err != nil {
+		return fmt.Sprintf("cannot recover sender: %s", err.Error())
+	}
+	args := &CallArgs{From: from, To: tx.To(), Value: tx.Value(), Data: tx.Data(), Gas: tx.Gas()}
+	parent := new(big.Int).Sub(blockNumber, big.NewInt(1))
+	ret, err := EthCall(rpcUrl, args, hexutil.EncodeBig(parent), nil)
+	if err != nil {
+		return err.Error()
+	}
+	// Nodes before the revert data was moved into the error object return it as the result.
+	if reason := DecodeRevertReason(ret); len(reason) != 0 {
+		return reason
+	}
+	return "unknown reason, the call succeeds when replayed, the transaction may have run out of gas"
+}
+
+// CheckTxReceipt returns an error with the decoded revert reason if tx was mined with a failed status.
+func CheckTxReceipt(rpcUrl string, ethClient *ethclient.Client, tx *types.Transaction, chainId *big.Int) error {
+	receipt, err := ethClient.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		return err
+	}
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return nil
+	}
+	return fmt.Errorf("transaction %s failed: %s", tx.Hash().String(), ReplayFailedTx(rpcUrl, tx, receipt.BlockNumber, chainId))
+}
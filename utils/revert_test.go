@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeRevertReason(t *testing.T) {
+	require.Equal(t, "only owner", DecodeRevertReason(encodeErrorString(t, "only owner")))
+	require.Equal(t, "", DecodeRevertReason([]byte{0x01, 0x02}))
+
+	panicData := append(append([]byte{}, panicSelector...), common.BigToHash(big.NewInt(0x11)).Bytes()...)
+	require.Equal(t, "panic 0x11: arithmetic overflow or underflow", DecodeRevertReason(panicData))
+}
+
+func TestDecodeCustomError(t *testing.T) {
+	saved := customErrors
+	defer func() { customErrors = saved }()
+	customErrors = loadCustomErrors(`[{"type":"error","name":"InsufficientAllowance","inputs":[{"name":"needed","type":"uint256"}]},{"type":"function","name":"approve","inputs":[]}]`)
+	require.Len(t, customErrors, 1)
+
+	data := append(crypto.Keccak256([]byte("InsufficientAllowance(uint256)"))[:4], common.BigToHash(big.NewInt(42)).Bytes()...)
+	require.Equal(t, "InsufficientAllowance(42)", DecodeRevertReason(data))
+}
@@ -0,0 +1,246 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// solcCompilers are the compilers looked up in PATH when none is configured, solc first and then solc-js.
+var solcCompilers = []string{"solc", "solcjs"}
+
+var (
+	solcVersionPattern = regexp.MustCompile(`\d+\.\d+\.\d+(\+commit\.[0-9a-f]+)?`)
+	solcImportPattern  = regexp.MustCompile(`import\s+(?:[^"';]*\s+from\s+)?["']([^"']+)["']`)
+)
+
+// SolcSettings selects a contract in a Solidity source file and how to compile it.
+type SolcSettings struct {
+	// Compiler is the path of solc or solcjs, looked up in PATH when empty.
+	Compiler        string
+	SourcePath      string
+	ContractName    string
+	CompilerVersion string
+	Optimizer       bool
+	OptimizerRuns   int
+	EVMVersion      string
+}
+
+// Compilation is the output of compiling a contract, with what is needed to verify it later.
+type Compilation struct {
+	ContractName      string          `json:"contract_name"`
+	SourceName        string          `json:"source_name"`
+	CompilerVersion   string          `json:"compiler_version"`
+	Optimizer         bool            `json:"optimizer"`
+	OptimizerRuns     int             `json:"optimizer_runs"`
+	EVMVersion        string          `json:"evm_version,omitempty"`
+	ABI               json.RawMessage `json:"abi"`
+	ByteCode          string          `json:"bytecode"`
+	Metadata          string          `json:"metadata"`
+	StandardJSONInput json.RawMessage `json:"standard_json_input"`
+}
+
+type solcSource struct {
+	Content string `json:"content"`
+}
+
+type solcInput struct {
+	Language string                `json:"language"`
+	Sources  map[string]solcSource `json:"sources"`
+	Settings solcInputSettings     `json:"settings"`
+}
+
+type solcInputSettings struct {
+	Optimizer struct {
+		Enabled bool `json:"enabled"`
+		Runs    int  `json:"runs"`
+	} `json:"optimizer"`
+	EVMVersion      string                         `json:"evmVersion,omitempty"`
+	OutputSelection map[string]map[string][]string `json:"outputSelection"`
+}
+
+type solcError struct {
+	Severity         string `json:"severity"`
+	Message          string `json:"message"`
+	FormattedMessage string `json:"formattedMessage"`
+}
+
+type solcContract struct {
+	ABI json.RawMessage `json:"abi"`
+	EVM struct {
+		ByteCode struct {
+			Object string `json:"object"`
+		} `json:"bytecode"`
+	} `json:"evm"`
+	Metadata string `json:"metadata"`
+}
+
+type solcOutput struct {
+	Errors    []solcError                        `json:"errors"`
+	Contracts map[string]map[string]solcContract `json:"contracts"`
+}
+
+// CompileSolidity compiles settings.SourcePath and its imports with a local solc or solc-js through the standard JSON
+// interface. The compiler version must match settings.CompilerVersion when it is set.
+func CompileSolidity(settings SolcSettings) (*Compilation, error) {
+	if len(settings.ContractName) == 0 {
+		return nil, fmt.Errorf("missing contract name to compile in %s", settings.SourcePath)
+	}
+	compiler, err := findSolc(settings.Compiler)
+	if err != nil {
+		return nil, err
+	}
+	version, err := solcVersion(compiler)
+	if err != nil {
+		return nil, err
+	}
+	if len(settings.CompilerVersion) != 0 && !compilerVersionMatches(version, settings.CompilerVersion) {
+		return nil, fmt.Errorf("%s is version %s, but the config requires %s", compiler, version, settings.CompilerVersion)
+	}
+
+	sourceName := filepath.Base(settings.SourcePath)
+	sources, err := collectSoliditySources(filepath.Dir(settings.SourcePath), sourceName)
+	if err != nil {
+		return nil, err
+	}
+	input := solcInput{Language: "Solidity", Sources: sources}
+	input.Settings.Optimizer.Enabled = settings.Optimizer
+	input.Settings.Optimizer.Runs = settings.OptimizerRuns
+	input.Settings.EVMVersion = settings.EVMVersion
+	input.Settings.OutputSelection = map[string]map[string][]string{
+		"*": {"*": {"abi", "evm.bytecode.object", "metadata"}},
+	}
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(compiler, "--standard-json")
+	cmd.Stdin = bytes.NewReader(inputJSON)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to run %s: %s %s", compiler, err.Error(), strings.TrimSpace(stderr.String()))
+	}
+	contract, err := parseSolcOutput(stdout.Bytes(), sourceName, settings.ContractName)
+	if err != nil {
+		return nil, err
+	}
+	return &Compilation{
+		ContractName:      settings.ContractName,
+		SourceName:        sourceName,
+		CompilerVersion:   "v" + version,
+		Optimizer:         settings.Optimizer,
+		OptimizerRuns:     settings.OptimizerRuns,
+		EVMVersion:        settings.EVMVersion,
+		ABI:               contract.ABI,
+		ByteCode:          contract.EVM.ByteCode.Object,
+		Metadata:          contract.Metadata,
+		StandardJSONInput: inputJSON,
+	}, nil
+}
+
+func findSolc(compiler string) (string, error) {
+	if len(compiler) != 0 {
+		found, err := exec.LookPath(compiler)
+		if err != nil {
+			return "", fmt.Errorf("solidity compiler %s not found: %s", compiler, err.Error())
+		}
+		return found, nil
+	}
+	for _, name := range solcCompilers {
+		if found, err := exec.LookPath(name); err == nil {
+			return found, nil
+		}
+	}
+	return "", fmt.Errorf("no solidity compiler found, install solc (https://docs.soliditylang.org/en/latest/installing-solidity.html) or solc-js (npm install -g solc) with the version in the config, or set the compiler path in the config")
+}
+
+// compilerVersionMatches reports whether version, like 0.5.16+commit.9c3226ce, is the required version. A required
+// version with a commit must match the long version, a platform suffix like .Linux.g++ aside. Otherwise the semver
+// before +commit must be equal, so that 0.5.1 does not match 0.5.16.
+func compilerVersionMatches(version, required string) bool {
+	required = strings.TrimPrefix(required, "v")
+	if strings.Contains(required, "+") {
+		return version == solcVersionPattern.FindString(required)
+	}
+	return strings.SplitN(version, "+", 2)[0] == required
+}
+
+// solcVersion returns the version of compiler, like "0.5.16+commit.9c3226ce".
+func solcVersion(compiler string) (string, error) {
+	output, err := exec.Command(compiler, "--version").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the version of %s: %s", compiler, err.Error())
+	}
+	version := solcVersionPattern.FindString(string(output))
+	if len(version) == 0 {
+		return "", fmt.Errorf("unexpected version output of %s: %s", compiler, strings.TrimSpace(string(output)))
+	}
+	return version, nil
+}
+
+// collectSoliditySources reads sourceName in dir and, recursively, the files it imports. Relative imports resolve
+// against the importing file, other imports against dir.
+func collectSoliditySources(dir, sourceName string) (map[string]solcSource, error) {
+	sources := make(map[string]solcSource)
+	pending := []string{sourceName}
+	for len(pending) != 0 {
+		name := pending[0]
+		pending = pending[1:]
+		if _, ok := sources[name]; ok {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read solidity source: %s", err.Error())
+		}
+		sources[name] = solcSource{Content: string(content)}
+		for _, match := range solcImportPattern.FindAllStringSubmatch(string(content), -1) {
+			imported := match[1]
+			if strings.HasPrefix(imported, ".") {
+				imported = path.Join(path.Dir(name), imported)
+			}
+			pending = append(pending, imported)
+		}
+	}
+	return sources, nil
+}
+
+func parseSolcOutput(output []byte, sourceName, contractName string) (*solcContract, error) {
+	var result solcOutput
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse the compiler output: %s", err.Error())
+	}
+	var errs []string
+	for _, solcErr := range result.Errors {
+		if solcErr.Severity == "error" {
+			message := solcErr.FormattedMessage
+			if len(message) == 0 {
+				message = solcErr.Message
+			}
+			errs = append(errs, strings.TrimSpace(message))
+		}
+	}
+	if len(errs) != 0 {
+		return nil, fmt.Errorf("failed to compile %s:\n%s", sourceName, strings.Join(errs, "\n"))
+	}
+	contract, ok := result.Contracts[sourceName][contractName]
+	if !ok {
+		return nil, fmt.Errorf("contract %s not found in %s", contractName, sourceName)
+	}
+	if len(contract.EVM.ByteCode.Object) == 0 {
+		return nil, fmt.Errorf("contract %s has no byte code, it may be abstract or an interface", contractName)
+	}
+	if strings.Contains(contract.EVM.ByteCode.Object, "__") {
+		return nil, fmt.Errorf("contract %s links external libraries, which is not supported", contractName)
+	}
+	return &contract, nil
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0755))
+}
+
+func TestCollectSoliditySources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solc")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeFile(t, filepath.Join(dir, "Token.sol"), `import "./lib/SafeMath.sol";
+import {Ownable} from "access/Ownable.sol";`)
+	writeFile(t, filepath.Join(dir, "lib", "SafeMath.sol"), `import "../access/Ownable.sol";`)
+	writeFile(t, filepath.Join(dir, "access", "Ownable.sol"), `contract Ownable {}`)
+
+	sources, err := collectSoliditySources(dir, "Token.sol")
+	require.NoError(t, err)
+	require.Len(t, sources, 3)
+	require.Contains(t, sources, "lib/SafeMath.sol")
+	require.Contains(t, sources, "access/Ownable.sol")
+
+	writeFile(t, filepath.Join(dir, "Broken.sol"), `import "./Missing.sol";`)
+	_, err = collectSoliditySources(dir, "Broken.sol")
+	require.Error(t, err)
+}
+
+func TestParseSolcOutput(t *testing.T) {
+	output := `{"contracts":{"Token.sol":{"Token":{"abi":[],"evm":{"bytecode":{"object":"6080"}},"metadata":"{}"},
+"Lib":{"abi":[],"evm":{"bytecode":{"object":"60__$abc$__"}}},"IToken":{"abi":[],"evm":{"bytecode":{"object":""}}}}},
+"errors":[{"severity":"warning","formattedMessage":"unused variable"}]}`
+	contract, err := parseSolcOutput([]byte(output), "Token.sol", "Token")
+	require.NoError(t, err)
+	require.Equal(t, "6080", contract.EVM.ByteCode.Object)
+
+	_, err = parseSolcOutput([]byte(output), "Token.sol", "Missing")
+	require.Error(t, err)
+	_, err = parseSolcOutput([]byte(output), "Token.sol", "Lib")
+	require.Error(t, err)
+	_, err = parseSolcOutput([]byte(output), "Token.sol", "IToken")
+	require.Error(t, err)
+
+	_, err = parseSolcOutput([]byte(`{"errors":[{"severity":"error","formattedMessage":"ParserError: expected ';'"}]}`), "Token.sol", "Token")
+	require.EqualError(t, err, "failed to compile Token.sol:\nParserError: expected ';'")
+}
+
+func TestCompileSolidity(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake compiler is a shell script")
+	}
+	dir, err := ioutil.TempDir("", "solc")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeFile(t, filepath.Join(dir, "Token.sol"), `contract Token {}`)
+	compiler := filepath.Join(dir, "solc")
+	writeFile(t, compiler, `#!/bin/sh
+if [ "$1" = "--version" ]; then
+  echo "solc, the solidity compiler commandline interface"
+  echo "Version: 0.5.16+commit.9c3226ce.Linux.g++"
+  exit 0
+fi
+cat > /dev/null
+echo '{"contracts":{"Token.sol":{"Token":{"abi":[],"evm":{"bytecode":{"object":"6080"}},"metadata":"{}"}}}}'
+`)
+
+	settings := SolcSettings{Compiler: compiler, SourcePath: filepath.Join(dir, "Token.sol"), ContractName: "Token", CompilerVersion: "v0.5.16", Optimizer: true, OptimizerRuns: 200}
+	compilation, err := CompileSolidity(settings)
+	require.NoError(t, err)
+	require.Equal(t, "6080", compilation.ByteCode)
+	require.Equal(t, "v0.5.16+commit.9c3226ce", compilation.CompilerVersion)
+	require.Contains(t, string(compilation.StandardJSONInput), `"optimizer":{"enabled":true,"runs":200}`)
+
+	for _, required := range []string{"0.5.16", "v0.5.16+commit.9c3226ce", "0.5.16+commit.9c3226ce.Linux.g++"} {
+		settings.CompilerVersion = required
+		_, err = CompileSolidity(settings)
+		require.NoError(t, err, required)
+	}
+
+	for _, required := range []string{"0.8.19", "0.5.1", "v0.5.1", "v0.5.16+commit.00000000", "0.5"} {
+		settings.CompilerVersion = required
+		_, err = CompileSolidity(settings)
+		require.Error(t, err, required)
+	}
+
+	settings.Compiler = filepath.Join(dir, "missing-solc")
+	_, err = CompileSolidity(settings)
+	require.Error(t, err)
+}
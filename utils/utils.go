@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"math/big"
-	"strings"
 	"time"
 
 	bindconst "github.com/binance-chain/token-bind-tool/const"
@@ -41,13 +40,13 @@ func GetCallOpts() *bind.CallOpts {
 	return callOpts
 }
 
-func DeployContract(ethClient *ethclient.Client, wallet *keystore.KeyStore, account accounts.Account, contractData hexutil.Bytes, chainId *big.Int) (common.Hash, error) {
-	gasLimit := hexutil.Uint64(bindconst.DefaultGasLimit)
+func DeployContract(ethClient *ethclient.Client, wallet *keystore.KeyStore, account accounts.Account, contractData hexutil.Bytes, gasLimitValue uint64, gasPriceValue *big.Int, chainId *big.Int) (common.Hash, error) {
+	gasLimit := hexutil.Uint64(gasLimitValue)
 	nonce, err := ethClient.PendingNonceAt(context.Background(), account.Address)
 	if err != nil {
 		return common.Hash{}, err
 	}
-	gasPrice := hexutil.Big(*big.NewInt(bindconst.DefaultGasPrice))
+	gasPrice := hexutil.Big(*gasPriceValue)
 	nonceUint64 := hexutil.Uint64(nonce)
 	sendTxArgs := &bindtypes.SendTxArgs{
 		From:     account.Address,
@@ -139,11 +138,14 @@ func toTransaction(args *bindtypes.SendTxArgs) *types.Transaction {
 }
 
 func PrintTxExplorerUrl(msg, txHash string, chainID *big.Int) {
+	fmt.Println(TxExplorerUrl(msg, txHash, chainID))
+}
+
+func TxExplorerUrl(msg, txHash string, chainID *big.Int) string {
 	if chainID.Cmp(big.NewInt(bindconst.MainnetChainID)) == 0 {
-		fmt.Println(fmt.Sprintf(bindconst.MainnetExplorerTxUrl, msg, txHash))
-	} else {
-		fmt.Println(fmt.Sprintf(bindconst.TestnetExplorerTxUrl, msg, txHash))
+		return fmt.Sprintf(bindconst.MainnetExplorerTxUrl, msg, txHash)
 	}
+	return fmt.Sprintf(bindconst.TestnetExplorerTxUrl, msg, txHash)
 }
 
 func PrintAddrExplorerUrl(msg, address string, chainID *big.Int) {
@@ -180,26 +182,3 @@ func SendTransactionFromLedger(rpcClient *ethclient.Client, wallet accounts.Wall
 	}
 	return signTx, rpcClient.SendTransaction(context.Background(), signTx)
 }
-
-func ValidateBSCAddr(addr string) error {
-	if !strings.HasPrefix(addr, "0x") || len(addr) != 42 {
-		return fmt.Errorf("invalid BEP20 owner account, expect bsc address, like 0x4E656459ed25bF986Eea1196Bc1B00665401645d")
-	}
-	return nil
-}
-
-func ConvertToBEP20Amount(amount *big.Int, decimals int64) *big.Int {
-	if decimals >= 8 {
-		precision := 1
-		for idx := int64(0); idx < decimals-8; idx++ {
-			precision *= 10
-		}
-		return big.NewInt(1).Mul(amount, big.NewInt(int64(precision)))
-	} else {
-		precision := 1
-		for idx := int64(0); idx < 8-decimals; idx++ {
-			precision *= 10
-		}
-		return big.NewInt(1).Div(amount, big.NewInt(int64(precision)))
-	}
-}
@@ -0,0 +1,273 @@
+package utils
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// VerificationBundle is what an Etherscan compatible block explorer needs to verify a contract from its sources.
+type VerificationBundle struct {
+	ContractAddress common.Address `json:"contract_address"`
+	// ContractName is the fully qualified name of the contract, like "contracts/Token.sol:Token".
+	ContractName    string `json:"contract_name"`
+	CompilerVersion string `json:"compiler_version"`
+	Optimizer       bool   `json:"optimizer"`
+	OptimizerRuns   int    `json:"optimizer_runs"`
+	EVMVersion      string `json:"evm_version,omitempty"`
+	// ConstructorArgs is the hex of the ABI encoded constructor arguments, without 0x.
+	ConstructorArgs   string          `json:"constructor_args"`
+	StandardJSONInput json.RawMessage `json:"standard_json_input"`
+
+	// ByteCode is the hex creation code of the build, without the constructor arguments and with placeholders for the
+	// libraries it links.
+	ByteCode string `json:"-"`
+}
+
+type standardJSONSettings struct {
+	Settings struct {
+		Optimizer struct {
+			Enabled bool `json:"enabled"`
+			Runs    int  `json:"runs"`
+		} `json:"optimizer"`
+		EVMVersion string `json:"evmVersion"`
+	} `json:"settings"`
+}
+
+type hardhatDebugFile struct {
+	BuildInfo string `json:"buildInfo"`
+}
+
+type hardhatBuildInfo struct {
+	SolcLongVersion string          `json:"solcLongVersion"`
+	Input           json.RawMessage `json:"input"`
+}
+
+type metadataSource struct {
+	Keccak256 string  `json:"keccak256"`
+	Content   *string `json:"content"`
+}
+
+type solcMetadata struct {
+	Language string `json:"language"`
+	Compiler struct {
+		Version string `json:"version"`
+	} `json:"compiler"`
+	Settings map[string]json.RawMessage `json:"settings"`
+	Sources  map[string]metadataSource  `json:"sources"`
+}
+
+// BundleFromCompilation builds the verification bundle of a contract compiled from a source in the deploy config.
+func BundleFromCompilation(compilation *Compilation) *VerificationBundle {
+	return &VerificationBundle{
+		ContractName:      compilation.SourceName + ":" + compilation.ContractName,
+		CompilerVersion:   compilation.CompilerVersion,
+		Optimizer:         compilation.Optimizer,
+		OptimizerRuns:     compilation.OptimizerRuns,
+		EVMVersion:        compilation.EVMVersion,
+		StandardJSONInput: compilation.StandardJSONInput,
+		ByteCode:          compilation.ByteCode,
+	}
+}
+
+// BundleFromArtifact builds the verification bundle of the artifact at artifactPath. A Hardhat artifact points to the
+// build info which holds the standard JSON input. Foundry and Truffle artifacts hold the solc metadata, the sources it
+// lists are read from sourceRoot, the project directory, and must not have changed since the build.
+func BundleFromArtifact(artifactPath string, artifact *Artifact, sourceRoot string) (*VerificationBundle, error) {
+	if artifact.Format == ArtifactHardhat {
+		return bundleFromBuildInfo(artifactPath, artifact)
+	}
+	if len(artifact.Metadata) == 0 {
+		return nil, fmt.Errorf("the %s artifact %s has no solc metadata", artifact.Format, artifactPath)
+	}
+	metadataJSON := []byte(artifact.Metadata)
+	if artifact.Metadata[0] == '"' {
+		var metadataStr string
+		if err := json.Unmarshal(artifact.Metadata, &metadataStr); err != nil {
+			return nil, fmt.Errorf("invalid metadata in %s: %s", artifactPath, err.Error())
+		}
+		metadataJSON = []byte(metadataStr)
+	}
+	bundle, err := BundleFromMetadata(metadataJSON, sourceRoot)
+	if err != nil {
+		return nil, err
+	}
+	bundle.ByteCode = artifact.ByteCode
+	return bundle, nil
+}
+
+func bundleFromBuildInfo(artifactPath string, artifact *Artifact) (*VerificationBundle, error) {
+	debugPath := strings.TrimSuffix(artifactPath, filepath.Ext(artifactPath)) + ".dbg.json"
+	data, err := ioutil.ReadFile(debugPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the hardhat debug file: %s", err.Error())
+	}
+	var debugFile hardhatDebugFile
+	if err := json.Unmarshal(data, &debugFile); err != nil || len(debugFile.BuildInfo) == 0 {
+		return nil, fmt.Errorf("invalid hardhat debug file %s", debugPath)
+	}
+	buildInfoPath := filepath.Join(filepath.Dir(debugPath), debugFile.BuildInfo)
+	data, err = ioutil.ReadFile(buildInfoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the hardhat build info: %s", err.Error())
+	}
+	var buildInfo hardhatBuildInfo
+	if err := json.Unmarshal(data, &buildInfo); err != nil {
+		return nil, fmt.Errorf("invalid hardhat build info %s: %s", buildInfoPath, err.Error())
+	}
+	var input standardJSONSettings
+	if err := json.Unmarshal(buildInfo.Input, &input); err != nil {
+		return nil, fmt.Errorf("invalid hardhat build info %s: %s", buildInfoPath, err.Error())
+	}
+	return &VerificationBundle{
+		ContractName:      artifact.SourceName + ":" + artifact.ContractName,
+		CompilerVersion:   "v" + buildInfo.SolcLongVersion,
+		Optimizer:         input.Settings.Optimizer.Enabled,
+		OptimizerRuns:     input.Settings.Optimizer.Runs,
+		EVMVersion:        input.Settings.EVMVersion,
+		StandardJSONInput: buildInfo.Input,
+		ByteCode:          artifact.ByteCode,
+	}, nil
+}
+
+// BundleFromMetadata rebuilds the standard JSON input of the contract described by the solc metadataJSON, reading the
+// sources it lists from sourceRoot. The bundle has no byte code.
+func BundleFromMetadata(metadataJSON []byte, sourceRoot string) (*VerificationBundle, error) {
+	var metadata solcMetadata
+	if err := json.Unmarshal(metadataJSON, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid solc metadata: %s", err.Error())
+	}
+	var target map[string]string
+	if err := json.Unmarshal(metadata.Settings["compilationTarget"], &target); err != nil || len(target) != 1 {
+		return nil, fmt.Errorf("invalid solc metadata, expect a single compilation target")
+	}
+	contractName := ""
+	for source, name := range target {
+		contractName = source + ":" + name
+	}
+	settings := make(map[string]json.RawMessage, len(metadata.Settings))
+	for key, value := range metadata.Settings {
+		settings[key] = value
+	}
+	delete(settings, "compilationTarget")
+	// The metadata keys libraries by "path:Lib", the standard JSON input by path, then by name.
+	if librariesJSON, ok := settings["libraries"]; ok {
+		var libraries map[string]string
+		if err := json.Unmarshal(librariesJSON, &libraries); err != nil {
+			return nil, fmt.Errorf("invalid solc metadata libraries: %s", err.Error())
+		}
+		nested := make(map[string]map[string]string)
+		for fullName, addr := range libraries {
+			source, name := "", fullName
+			if idx := strings.LastIndex(fullName, ":"); idx >= 0 {
+				source, name = fullName[:idx], fullName[idx+1:]
+			}
+			if nested[source] == nil {
+				nested[source] = make(map[string]string)
+			}
+			nested[source][name] = addr
+		}
+		data, err := json.Marshal(nested)
+		if err != nil {
+			return nil, err
+		}
+		settings["libraries"] = data
+	}
+	settings["outputSelection"] = json.RawMessage(`{"*":{"*":["abi","evm.bytecode.object","metadata"]}}`)
+
+	sourceNames := make([]string, 0, len(metadata.Sources))
+	for name := range metadata.Sources {
+		sourceNames = append(sourceNames, name)
+	}
+	sort.Strings(sourceNames)
+	sources := make(map[string]solcSource, len(metadata.Sources))
+	for _, name := range sourceNames {
+		source := metadata.Sources[name]
+		content := ""
+		if source.Content != nil {
+			content = *source.Content
+		} else {
+			var err error
+			if content, err = readMetadataSource(sourceRoot, name); err != nil {
+				return nil, err
+			}
+		}
+		if len(source.Keccak256) != 0 && !strings.EqualFold(crypto.Keccak256Hash([]byte(content)).Hex(), source.Keccak256) {
+			return nil, fmt.Errorf("source %s changed since the build, its keccak256 differs from the metadata", name)
+		}
+		sources[name] = solcSource{Content: content}
+	}
+	language := metadata.Language
+	if len(language) == 0 {
+		language = "Solidity"
+	}
+	input, err := json.Marshal(struct {
+		Language string                     `json:"language"`
+		Sources  map[string]solcSource      `json:"sources"`
+		Settings map[string]json.RawMessage `json:"settings"`
+	}{language, sources, settings})
+	if err != nil {
+		return nil, err
+	}
+	var inputSettings standardJSONSettings
+	if err := json.Unmarshal(input, &inputSettings); err != nil {
+		return nil, fmt.Errorf("invalid solc metadata settings: %s", err.Error())
+	}
+	return &VerificationBundle{
+		ContractName:      contractName,
+		CompilerVersion:   "v" + strings.TrimPrefix(metadata.Compiler.Version, "v"),
+		Optimizer:         inputSettings.Settings.Optimizer.Enabled,
+		OptimizerRuns:     inputSettings.Settings.Optimizer.Runs,
+		EVMVersion:        inputSettings.Settings.EVMVersion,
+		StandardJSONInput: input,
+	}, nil
+}
+
+// readMetadataSource reads the source name of the metadata, relative to sourceRoot or to its node_modules, or an
+// absolute path. Truffle prefixes project sources with "project:/".
+func readMetadataSource(sourceRoot, name string) (string, error) {
+	relative := strings.TrimPrefix(name, "project:/")
+	candidates := []string{filepath.Join(sourceRoot, relative), filepath.Join(sourceRoot, "node_modules", relative)}
+	if filepath.IsAbs(name) {
+		candidates = append([]string{name}, candidates...)
+	}
+	for _, candidate := range candidates {
+		data, err := ioutil.ReadFile(candidate)
+		if err == nil {
+			return string(data), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to read source %s: %s", name, err.Error())
+		}
+	}
+	return "", fmt.Errorf("source %s not found in %s, set the project directory", name, sourceRoot)
+}
+
+// ConstructorArgsFromInitCode returns the hex constructor arguments at the end of initCode, which must start with the
+// hex byteCode. A library placeholder in byteCode matches any address.
+func ConstructorArgsFromInitCode(initCode []byte, byteCode string) (string, error) {
+	initCodeHex := hex.EncodeToString(initCode)
+	if len(initCodeHex) < len(byteCode) {
+		return "", fmt.Errorf("the deployed init code is shorter than the byte code of the build")
+	}
+	wildcard := make([]bool, len(byteCode))
+	for _, loc := range libraryPlaceholderPattern.FindAllStringIndex(byteCode, -1) {
+		for idx := loc[0]; idx < loc[1]; idx++ {
+			wildcard[idx] = true
+		}
+	}
+	for idx := range byteCode {
+		if !wildcard[idx] && !strings.EqualFold(byteCode[idx:idx+1], initCodeHex[idx:idx+1]) {
+			return "", fmt.Errorf("the deployed init code differs from the byte code of the build at byte %d", idx/2)
+		}
+	}
+	return initCodeHex[len(byteCode):], nil
+}
@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+const tokenSource = "pragma solidity 0.8.19;\ncontract Token {}\n"
+
+func TestBundleFromMetadata(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verify")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "src", "Token.sol"), []byte(tokenSource), 0644))
+
+	metadata := `{
+  "compiler": {"version": "0.8.19+commit.7dd6d404"},
+  "language": "Solidity",
+  "settings": {
+    "compilationTarget": {"src/Token.sol": "Token"},
+    "evmVersion": "paris",
+    "libraries": {"src/Lib.sol:Lib": "0x1111111111111111111111111111111111111111"},
+    "metadata": {"bytecodeHash": "ipfs"},
+    "optimizer": {"enabled": true, "runs": 200},
+    "remappings": []
+  },
+  "sources": {"src/Token.sol": {"keccak256": "` + crypto.Keccak256Hash([]byte(tokenSource)).Hex() + `", "urls": []}}
+}`
+	bundle, err := BundleFromMetadata([]byte(metadata), dir)
+	require.NoError(t, err)
+	require.Equal(t, "src/Token.sol:Token", bundle.ContractName)
+	require.Equal(t, "v0.8.19+commit.7dd6d404", bundle.CompilerVersion)
+	require.True(t, bundle.Optimizer)
+	require.Equal(t, 200, bundle.OptimizerRuns)
+	require.Equal(t, "paris", bundle.EVMVersion)
+
+	var input struct {
+		Sources  map[string]solcSource      `json:"sources"`
+		Settings map[string]json.RawMessage `json:"settings"`
+	}
+	require.NoError(t, json.Unmarshal(bundle.StandardJSONInput, &input))
+	require.Equal(t, tokenSource, input.Sources["src/Token.sol"].Content)
+	require.NotContains(t, input.Settings, "compilationTarget")
+	require.JSONEq(t, `{"src/Lib.sol": {"Lib": "0x1111111111111111111111111111111111111111"}}`, string(input.Settings["libraries"]))
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "src", "Token.sol"), []byte(tokenSource+"// changed\n"), 0644))
+	_, err = BundleFromMetadata([]byte(metadata), dir)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "src/Token.sol changed since the build")
+
+	_, err = BundleFromMetadata([]byte(metadata), filepath.Join(dir, "missing"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not found")
+}
+
+func TestBundleFromHardhatArtifact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verify")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	artifactDir := filepath.Join(dir, "artifacts", "contracts", "Token.sol")
+	require.NoError(t, os.MkdirAll(artifactDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "artifacts", "build-info"), 0755))
+
+	input := `{"language":"Solidity","sources":{"contracts/Token.sol":{"content":"contract Token {}"}},"settings":{"optimizer":{"enabled":false,"runs":200}}}`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "artifacts", "build-info", "abc.json"),
+		[]byte(`{"solcVersion":"0.8.19","solcLongVersion":"0.8.19+commit.7dd6d404","input":`+input+`}`), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(artifactDir, "Token.dbg.json"),
+		[]byte(`{"_format":"hh-sol-dbg-1","buildInfo":"../../build-info/abc.json"}`), 0644))
+	artifactPath := filepath.Join(artifactDir, "Token.json")
+	require.NoError(t, ioutil.WriteFile(artifactPath,
+		[]byte(`{"_format":"hh-sol-artifact-1","contractName":"Token","sourceName":"contracts/Token.sol","abi":[],"bytecode":"0x6080","linkReferences":{}}`), 0644))
+
+	artifact, err := ReadArtifact(artifactPath)
+	require.NoError(t, err)
+	bundle, err := BundleFromArtifact(artifactPath, artifact, dir)
+	require.NoError(t, err)
+	require.Equal(t, "contracts/Token.sol:Token", bundle.ContractName)
+	require.Equal(t, "v0.8.19+commit.7dd6d404", bundle.CompilerVersion)
+	require.False(t, bundle.Optimizer)
+	require.JSONEq(t, input, string(bundle.StandardJSONInput))
+	require.Equal(t, "6080", bundle.ByteCode)
+}
+
+func TestConstructorArgsFromInitCode(t *testing.T) {
+	libAddr := strings.Repeat("11", common.AddressLength)
+	byteCode := "608060" + "73" + "__Lib" + strings.Repeat("_", 35) + "00"
+	initCode, err := hex.DecodeString("608060" + "73" + libAddr + "00" + "0012")
+	require.NoError(t, err)
+	args, err := ConstructorArgsFromInitCode(initCode, byteCode)
+	require.NoError(t, err)
+	require.Equal(t, "0012", args)
+
+	_, err = ConstructorArgsFromInitCode(initCode, "608061")
+	require.EqualError(t, err, "the deployed init code differs from the byte code of the build at byte 2")
+	_, err = ConstructorArgsFromInitCode(initCode[:2], byteCode)
+	require.Error(t, err)
+}
+
+func TestEtherscanClient(t *testing.T) {
+	checks := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "key", r.Form.Get("apikey"))
+		switch r.Form.Get("action") {
+		case "verifysourcecode":
+			require.Equal(t, "solidity-standard-json-input", r.Form.Get("codeformat"))
+			require.Equal(t, "src/Token.sol:Token", r.Form.Get("contractname"))
+			require.Equal(t, "0012", r.Form.Get("constructorArguements"))
+			w.Write([]byte(`{"status":"1","message":"OK","result":"guid-1"}`))
+		case "checkverifystatus":
+			require.Equal(t, "guid-1", r.Form.Get("guid"))
+			checks++
+			if checks == 1 {
+				w.Write([]byte(`{"status":"0","message":"NOTOK","result":"Pending in queue"}`))
+			} else {
+				w.Write([]byte(`{"status":"1","message":"OK","result":"Pass - Verified"}`))
+			}
+		}
+	}))
+	defer server.Close()
+
+	client := NewEtherscanClient(server.URL, "key")
+	guid, err := client.SubmitVerification(&VerificationBundle{ContractName: "src/Token.sol:Token", ConstructorArgs: "0012", StandardJSONInput: json.RawMessage(`{}`)})
+	require.NoError(t, err)
+	require.Equal(t, "guid-1", guid)
+	status, pending, err := client.CheckVerification(guid)
+	require.NoError(t, err)
+	require.True(t, pending)
+	require.Equal(t, "Pending in queue", status)
+	status, pending, err = client.CheckVerification(guid)
+	require.NoError(t, err)
+	require.False(t, pending)
+	require.Equal(t, "Pass - Verified", status)
+}